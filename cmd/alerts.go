@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/rule"
+	"github.com/congregalis/stock-ping/store"
+)
+
+// RunAlerts executes the alerts subcommand
+func RunAlerts(args []string) {
+	fs := flag.NewFlagSet("alerts", flag.ExitOnError)
+
+	symbol := fs.String("symbol", "", "Only show alerts for this symbol or portfolio rule")
+	ruleID := fs.String("rule", "", "Only show alerts of this rule ID")
+	from := fs.String("from", "", "Start time (YYYY-MM-DD or YYYY-MM-DD HH:MM, default: 7 days ago unless --to is set)")
+	to := fs.String("to", "", "End time (YYYY-MM-DD for the whole day, or YYYY-MM-DD HH:MM, default: now)")
+	severity := fs.String("severity", "info", "Only show alerts of at least this severity: info, warning or critical")
+	limit := fs.Int("limit", 0, "Only show the most recent N alerts (0 = all)")
+	asJSON := fs.Bool("json", false, "Export alerts as JSON")
+	asCSV := fs.Bool("csv", false, "Export alerts as CSV")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stock-ping alerts [options]\n\n")
+		i18n.Fprintf(os.Stderr, "Show the alerts fired by watch and dashboard and how they were delivered.\n\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		i18n.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping alerts\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping alerts --symbol AAPL --severity warning\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping alerts --from 2024-06-01 --to 2024-06-30 --csv > alerts.csv\n")
+	}
+
+	fs.Parse(args)
+
+	if *asJSON && *asCSV {
+		i18n.Fprintf(os.Stderr, "Error: use either --json or --csv\n")
+		os.Exit(1)
+	}
+
+	minSeverity, err := config.ParseSeverity(*severity)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	filter := store.AlertFilter{
+		Subject:     *symbol,
+		RuleID:      *ruleID,
+		MinSeverity: minSeverity,
+	}
+	if *from == "" && *to == "" {
+		filter.From = time.Now().AddDate(0, 0, -7)
+	}
+	if *from != "" {
+		if filter.From, err = parseTimeFlag(*from); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --from: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *to != "" {
+		if filter.To, err = parseTimeFlag(*to); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --to: %v\n", err)
+			os.Exit(1)
+		}
+		if !strings.Contains(*to, ":") {
+			filter.To = filter.To.AddDate(0, 0, 1).Add(-time.Nanosecond) // Include the whole day
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	alertLog, err := store.NewAlertLog(cfg.History)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error opening alert history: %v\n", err)
+		os.Exit(1)
+	}
+
+	records, err := alertLog.Query(filter)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error querying alert history: %v\n", err)
+		os.Exit(1)
+	}
+	if *limit > 0 && len(records) > *limit {
+		records = records[len(records)-*limit:]
+	}
+
+	switch {
+	case *asJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if records == nil {
+			records = []store.AlertRecord{}
+		}
+		if err := enc.Encode(records); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case *asCSV:
+		if err := writeAlertsCSV(records); err != nil {
+			i18n.Fprintf(os.Stderr, "Error writing CSV: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		printAlerts(records)
+	}
+}
+
+// printAlerts prints the alerts as one line each, oldest first
+func printAlerts(records []store.AlertRecord) {
+	if len(records) == 0 {
+		i18n.Println("No alerts recorded for this selection.")
+		return
+	}
+
+	width := 0
+	for _, r := range records {
+		width = max(width, len([]rune(r.Subject)))
+	}
+
+	i18n.Printf("🔔 Alert history (%d alerts)\n", len(records))
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	for _, r := range records {
+		text := fmt.Sprintf("[%s] %s", r.RuleID, r.Text)
+		if icon := rule.SeverityIcon(r.Severity); icon != "" {
+			text = icon + " " + text
+		}
+		delivery := r.DeliveryText()
+		if r.Error != "" {
+			delivery += ": " + r.Error
+		}
+		fmt.Printf("%s  %-*s  %s  → %s\n", r.Time.Local().Format("2006-01-02 15:04:05"), width, r.Subject, text, delivery)
+	}
+}
+
+// writeAlertsCSV writes the alerts to stdout as CSV with a header row
+func writeAlertsCSV(records []store.AlertRecord) error {
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"time", "subject", "rule_id", "type", "severity", "text", "threshold", "value",
+		"price", "change_percent", "channels", "delivery", "via", "error"})
+
+	formatFloat := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	for _, r := range records {
+		var price, change string
+		if r.Quote != nil {
+			price = formatFloat(r.Quote.Price)
+			change = formatFloat(r.Quote.PercentChange)
+		}
+		w.Write([]string{
+			r.Time.Format(time.RFC3339),
+			r.Subject,
+			r.RuleID,
+			r.Type,
+			string(r.Severity),
+			r.Text,
+			formatFloat(r.Threshold),
+			formatFloat(r.Value),
+			price,
+			change,
+			strings.Join(r.Channels, ";"),
+			r.Delivery,
+			r.Via,
+			r.Error,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
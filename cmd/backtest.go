@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/rule"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// RunBacktest executes the backtest subcommand
+func RunBacktest(args []string) {
+	fs := flag.NewFlagSet("backtest", flag.ExitOnError)
+
+	symbol := fs.String("symbol", "", "Stock symbol whose rules to replay (required)")
+	id := fs.String("id", "", "Only replay the rule with this ID")
+	from := fs.String("from", "", "Start date (YYYY-MM-DD or YYYY-MM-DD HH:MM, default: 1 year ago)")
+	to := fs.String("to", "", "End date (YYYY-MM-DD or YYYY-MM-DD HH:MM, default: now)")
+	resolution := fs.String("resolution", "D", "Bars to replay: D (daily), 60, 30, 15, 5 or 1 minutes")
+	asJSON := fs.Bool("json", false, "Output the report as JSON")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stock-ping backtest [options]\n\n")
+		i18n.Fprintf(os.Stderr, "Replay historical candles through a symbol's rules and report the alerts they would have sent.\n\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		i18n.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping backtest --symbol AAPL --from 2024-01-01\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping backtest --symbol AAPL --id aapl-2 --from 2024-06-01 --json\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping backtest --symbol TSLA --resolution 15 --from \"2024-06-03 09:30\"\n")
+	}
+
+	fs.Parse(args)
+
+	if *symbol == "" {
+		i18n.Fprintf(os.Stderr, "Error: --symbol is required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if _, err := strconv.Atoi(*resolution); err != nil && *resolution != "D" {
+		i18n.Fprintf(os.Stderr, "Error: invalid --resolution %q (use D, 60, 30, 15, 5 or 1)\n", *resolution)
+		os.Exit(1)
+	}
+
+	end := time.Now()
+	start := end.AddDate(-1, 0, 0)
+	var err error
+	if *from != "" {
+		if start, err = parseTimeFlag(*from); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --from: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *to != "" {
+		if end, err = parseTimeFlag(*to); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --to: %v\n", err)
+			os.Exit(1)
+		}
+		if !strings.Contains(*to, ":") {
+			end = end.AddDate(0, 0, 1).Add(-time.Second) // Include the whole day
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	var rules []*config.Rule
+	for _, r := range cfg.RulesForSymbol(*symbol) {
+		if *id == "" || r.ID == *id {
+			rules = append(rules, r)
+		}
+	}
+	if len(rules) == 0 {
+		i18n.Fprintf(os.Stderr, "Error: no rules found for %s\n", *symbol)
+		i18n.Fprintf(os.Stderr, "Add one with: stock-ping config add --symbol %s --price-above 200\n", *symbol)
+		os.Exit(1)
+	}
+
+	_, market := cfg.SymbolInfo(*symbol)
+	holding := cfg.GetHolding(*symbol)
+	series, err := fetchBacktestCandles(stock.NewClient(cfg.Finnhub.APIKey), *symbol, market, *resolution, rules, holding, start, end)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error fetching candles: %v\n", err)
+		os.Exit(1)
+	}
+
+	report := rule.Backtest(rule.NewEvaluator(), rules, holding, *symbol, market, *resolution, series, start, end)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	printBacktestReport(report, market)
+}
+
+// fetchBacktestCandles fetches the bars to replay plus the daily and intraday
+// candles the rules need, with enough history before start to warm up
+// indicators and enough daily bars after end for forward returns
+func fetchBacktestCandles(client *stock.Client, symbol, market, resolution string, rules []*config.Rule, h *config.Holding, start, end time.Time) (map[string]*stock.Candle, error) {
+	needs := rule.CandleNeeds(rules, h)
+	lastDays := rule.ForwardDays[len(rule.ForwardDays)-1]
+	now := time.Now()
+
+	series := make(map[string]*stock.Candle)
+	warmup := needs["D"]
+	if resolution == "D" {
+		warmup = max(warmup, 1) // The previous close of the first bar
+	}
+	dailyFrom := start.AddDate(0, 0, -(warmup*3/2 + 10))
+	dailyTo := min(end.AddDate(0, 0, lastDays*3/2+10).Unix(), now.Unix())
+	daily, err := client.GetCandles(symbol, market, "D", dailyFrom.Unix(), dailyTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch daily candles: %w", err)
+	}
+	series["D"] = daily
+
+	if resolution != "D" {
+		needs[resolution] = max(needs[resolution], 1)
+	}
+	for res, bars := range needs {
+		if res == "D" {
+			continue
+		}
+		minutes, _ := strconv.Atoi(res)
+		from := start.Add(-time.Duration(bars*minutes)*time.Minute*6 - 4*24*time.Hour)
+		c, err := client.GetCandles(symbol, market, res, from.Unix(), end.Unix())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s-minute candles: %w", res, err)
+		}
+		series[res] = c
+	}
+	return series, nil
+}
+
+// printBacktestReport prints the simulated alerts and forward return statistics
+func printBacktestReport(report *rule.BacktestReport, market string) {
+	loc := stock.Location(market)
+	layout := "2006-01-02 15:04"
+	if report.Resolution == "D" {
+		layout = "2006-01-02"
+	}
+
+	i18n.Printf("🧪 Backtest %s (%s, %s → %s, %d bars)\n", report.Symbol, report.Resolution,
+		report.From.Format("2006-01-02"), report.To.Format("2006-01-02"), report.Bars)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	if len(report.Alerts) == 0 {
+		i18n.Println("No alerts would have been sent.")
+		return
+	}
+
+	i18n.Println("Time             Rule                Price      +1d      +5d     +20d  Reason")
+	for _, a := range report.Alerts {
+		fmt.Printf("%-16s %-14s %10.2f", a.Time.In(loc).Format(layout), a.RuleID, a.Price)
+		for _, days := range rule.ForwardDays {
+			if r, ok := a.Returns[days]; ok {
+				fmt.Printf(" %+7.2f%%", r)
+			} else {
+				fmt.Printf(" %8s", "-")
+			}
+		}
+		fmt.Printf("  %s\n", a.Text)
+	}
+
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	i18n.Printf("📊 %d alerts\n", len(report.Alerts))
+	i18n.Println("Horizon   Count      Mean    Median  Win rate      Best     Worst")
+	for _, s := range report.Stats {
+		if s.Count == 0 {
+			fmt.Printf("%-8s %6d %9s %9s %9s %9s %9s\n", fmt.Sprintf("+%dd", s.Days), 0, "-", "-", "-", "-", "-")
+			continue
+		}
+		fmt.Printf("%-8s %6d %+8.2f%% %+8.2f%% %8.1f%% %+8.2f%% %+8.2f%%\n",
+			fmt.Sprintf("+%dd", s.Days), s.Count, s.Mean, s.Median, s.WinRate, s.Best, s.Worst)
+	}
+}
@@ -3,9 +3,15 @@ package cmd
 import (
 	"flag"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/rule"
 )
 
 // RunConfig executes the config subcommand
@@ -22,6 +28,12 @@ func RunConfig(args []string) {
 		runConfigAdd(args[1:])
 	case "remove":
 		runConfigRemove(args[1:])
+	case "portfolio":
+		runConfigPortfolio(args[1:])
+	case "enable":
+		runConfigSetEnabled(args[1:], true)
+	case "disable":
+		runConfigSetEnabled(args[1:], false)
 	default:
 		printConfigUsage()
 		os.Exit(1)
@@ -30,26 +42,29 @@ func RunConfig(args []string) {
 
 func printConfigUsage() {
 	fmt.Fprintf(os.Stderr, "Usage: stock-ping config <command>\n\n")
-	fmt.Fprintf(os.Stderr, "Commands:\n")
-	fmt.Fprintf(os.Stderr, "  list                 List all monitoring rules\n")
-	fmt.Fprintf(os.Stderr, "  add                  Add a new monitoring rule\n")
-	fmt.Fprintf(os.Stderr, "  remove               Remove a monitoring rule\n")
+	i18n.Fprintf(os.Stderr, "Commands:\n")
+	i18n.Fprintf(os.Stderr, "  list                 List all monitoring rules\n")
+	i18n.Fprintf(os.Stderr, "  add                  Add a new monitoring rule (or update one with --id)\n")
+	i18n.Fprintf(os.Stderr, "  portfolio            Add a portfolio rule (or update one with --id)\n")
+	i18n.Fprintf(os.Stderr, "  remove               Remove a monitoring rule by ID\n")
+	i18n.Fprintf(os.Stderr, "  enable               Enable a monitoring rule by ID\n")
+	i18n.Fprintf(os.Stderr, "  disable              Disable a monitoring rule by ID\n")
 }
 
 func runConfigList(args []string) {
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
-	if len(cfg.Rules) == 0 {
-		fmt.Println("No monitoring rules configured.")
-		fmt.Println("Add rules with: stock-ping config add --symbol AAPL --price-above 200")
+	if len(cfg.Rules) == 0 && len(cfg.PortfolioRules) == 0 {
+		i18n.Println("No monitoring rules configured.")
+		i18n.Println("Add rules with: stock-ping config add --symbol AAPL --price-above 200")
 		return
 	}
 
-	fmt.Printf("📋 Monitoring Rules (%d)\n", len(cfg.Rules))
+	i18n.Printf("📋 Monitoring Rules (%d)\n", len(cfg.Rules))
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	for i, r := range cfg.Rules {
@@ -57,29 +72,233 @@ func runConfigList(args []string) {
 		if r.Name != "" {
 			displayName = fmt.Sprintf("%s (%s)", r.Symbol, r.Name)
 		}
-		fmt.Printf("%d. %s\n", i+1, displayName)
+		status := ""
+		if !r.IsEnabled() {
+			status = i18n.T(" (disabled)")
+		}
+		fmt.Printf("%d. [%s] %s%s\n", i+1, r.ID, displayName, status)
 
 		if r.PriceAbove != nil {
-			fmt.Printf("   • 价格高于 $%.2f\n", *r.PriceAbove)
+			i18n.Printf("   • Price above $%.2f\n", *r.PriceAbove)
 		}
 		if r.PriceBelow != nil {
-			fmt.Printf("   • 价格低于 $%.2f\n", *r.PriceBelow)
+			i18n.Printf("   • Price below $%.2f\n", *r.PriceBelow)
 		}
 		if r.ChangeAbove != nil {
-			fmt.Printf("   • 涨幅超过 %.2f%%\n", *r.ChangeAbove)
+			i18n.Printf("   • Up more than %.2f%%\n", *r.ChangeAbove)
 		}
 		if r.ChangeBelow != nil {
-			fmt.Printf("   • 跌幅超过 %.2f%%\n", *r.ChangeBelow)
+			i18n.Printf("   • Down more than %.2f%%\n", *r.ChangeBelow)
+		}
+		if r.LimitHit {
+			i18n.Printf("   • Limit up/down\n")
+		}
+		if r.LimitNear != nil {
+			i18n.Printf("   • Within %.2f%% of limit up/down\n", *r.LimitNear)
+		}
+		if r.GainAbove != nil {
+			i18n.Printf("   • Gain above %.2f%% (take profit)\n", *r.GainAbove)
+		}
+		if r.LossBelow != nil {
+			i18n.Printf("   • Loss below %.2f%% (stop loss)\n", *r.LossBelow)
+		}
+		if r.PLAbove != nil {
+			i18n.Printf("   • Position P/L above $%.2f\n", *r.PLAbove)
+		}
+		if r.PLBelow != nil {
+			i18n.Printf("   • Position P/L below $%.2f\n", *r.PLBelow)
+		}
+		if r.TrailingStopPct != nil {
+			i18n.Printf("   • Trailing stop: %.2f%% below the peak\n", *r.TrailingStopPct)
+		}
+		if r.TrailingStopAmount != nil {
+			i18n.Printf("   • Trailing stop: $%.2f below the peak\n", *r.TrailingStopAmount)
+		}
+		if r.GapAbove != nil {
+			i18n.Printf("   • Gap up more than %.2f%%\n", *r.GapAbove)
+		}
+		if r.GapBelow != nil {
+			i18n.Printf("   • Gap down more than %.2f%%\n", *r.GapBelow)
+		}
+		if r.NewDayHighAfter != nil {
+			i18n.Printf("   • New intraday high %s after the open\n", r.NewDayHighAfter)
+		}
+		if r.NewDayLowAfter != nil {
+			i18n.Printf("   • New intraday low %s after the open\n", r.NewDayLowAfter)
+		}
+		if r.BreakPrevHigh {
+			i18n.Printf("   • Breaks the previous day's high\n")
+		}
+		if r.BreakPrevLow {
+			i18n.Printf("   • Breaks the previous day's low\n")
+		}
+		if r.NewHighDays > 0 {
+			i18n.Printf("   • %d-day high\n", r.NewHighDays)
+		}
+		if r.NewLowDays > 0 {
+			i18n.Printf("   • %d-day low\n", r.NewLowDays)
+		}
+		for _, pc := range r.Changes {
+			if pc.Above != nil {
+				i18n.Printf("   • Change %s above %.2f%%\n", rule.ChangeWindow(pc), *pc.Above)
+			}
+			if pc.Below != nil {
+				i18n.Printf("   • Change %s below %.2f%%\n", rule.ChangeWindow(pc), *pc.Below)
+			}
+		}
+		if p := r.Pair; p != nil {
+			leg := p.Symbol
+			if p.GetMultiplier() != 1 {
+				leg = fmt.Sprintf("%g×%s", p.GetMultiplier(), leg)
+			}
+			if p.FX != "" {
+				leg = fmt.Sprintf("%s×%s", leg, p.FX)
+			}
+			i18n.Printf("   • Pair: %s vs %s (%s)\n", r.Symbol, leg, p.GetFormula())
+			if p.Above != nil {
+				i18n.Printf("     - above %g\n", *p.Above)
+			}
+			if p.Below != nil {
+				i18n.Printf("     - below %g\n", *p.Below)
+			}
+			if p.ZScore > 0 {
+				i18n.Printf("     - %[2]g standard deviations from the %[1]d-day mean\n", p.GetWindow(), p.ZScore)
+			}
+		}
+		if x := r.Crossovers; x != nil {
+			bar := i18n.T("daily")
+			if res := x.GetResolution(); res != "D" {
+				bar = i18n.T("%s-minute", res)
+			}
+			for _, n := range x.PriceSMA {
+				i18n.Printf("   • Price crosses SMA(%d) (%s)\n", n, bar)
+			}
+			if len(x.SMACross) == 2 {
+				i18n.Printf("   • SMA(%d)/SMA(%d) golden/death cross (%s)\n", x.SMACross[0], x.SMACross[1], bar)
+			}
+			if x.MACDSignal {
+				i18n.Printf("   • MACD crosses its signal line (%s)\n", bar)
+			}
+			if x.RSI > 0 {
+				overbought, oversold := x.RSILevels()
+				i18n.Printf("   • RSI(%d) in/out of overbought %.0f / oversold %.0f (%s)\n", x.RSI, overbought, oversold, bar)
+			}
+		}
+		if r.When != "" {
+			i18n.Printf("   • When: %s\n", r.When)
+		}
+		if a := r.Active; a != nil {
+			if len(a.Days) > 0 {
+				i18n.Printf("   🕒 Active days: %s\n", strings.Join(a.Days, ","))
+			}
+			if len(a.Times) > 0 {
+				i18n.Printf("   🕒 Active times: %s\n", strings.Join(a.Times, ", "))
+			}
+			if a.First > 0 {
+				i18n.Printf("   🕒 Only within %s after the open\n", a.First)
+			}
+		}
+		printAlertPolicy(r.AlertPolicy)
+	}
+
+	if len(cfg.PortfolioRules) > 0 {
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		i18n.Printf("💼 Portfolio Rules (%d)\n", len(cfg.PortfolioRules))
+	}
+	for i, r := range cfg.PortfolioRules {
+		status := ""
+		if !r.IsEnabled() {
+			status = i18n.T(" (disabled)")
+		}
+		name := ""
+		if r.Name != "" {
+			name = " " + r.Name
+		}
+		fmt.Printf("%d. [%s]%s%s\n", i+1, r.ID, name, status)
+
+		if r.ValueAbove != nil {
+			i18n.Printf("   • Total value above $%.2f\n", *r.ValueAbove)
+		}
+		if r.ValueBelow != nil {
+			i18n.Printf("   • Total value below $%.2f\n", *r.ValueBelow)
+		}
+		if r.PLAbove != nil {
+			i18n.Printf("   • Total P/L above $%.2f\n", *r.PLAbove)
 		}
+		if r.PLBelow != nil {
+			i18n.Printf("   • Total P/L below $%.2f\n", *r.PLBelow)
+		}
+		if r.PLPctAbove != nil {
+			i18n.Printf("   • Total return above %.2f%%\n", *r.PLPctAbove)
+		}
+		if r.PLPctBelow != nil {
+			i18n.Printf("   • Total return below %.2f%%\n", *r.PLPctBelow)
+		}
+		if r.DayChangeAbove != nil {
+			i18n.Printf("   • Up more than %.2f%% today\n", *r.DayChangeAbove)
+		}
+		if r.DayChangeBelow != nil {
+			i18n.Printf("   • Down more than %.2f%% today\n", *r.DayChangeBelow)
+		}
+		printAlertPolicy(r.AlertPolicy)
 	}
 
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("Config file: %s\n", config.DefaultConfigPath())
+	i18n.Printf("Config file: %s\n", config.DefaultConfigPath())
+}
+
+// printAlertPolicy prints the cooldown, re-arm band, repeat interval and severity of a rule
+func printAlertPolicy(p config.AlertPolicy) {
+	if p.Cooldown > 0 {
+		i18n.Printf("   ⏱ Cooldown: %s\n", p.Cooldown)
+	}
+	if p.RearmBand != nil {
+		i18n.Printf("   ⏱ Re-arms after moving back %s\n", p.RearmBand)
+	}
+	if p.RepeatEvery > 0 {
+		i18n.Printf("   ⏱ Repeats every %s while met\n", p.RepeatEvery)
+	}
+	if p.Severity != "" || len(p.ConditionSeverity) > 0 {
+		i18n.Printf("   🚦 Severity: %s\n", p.SeverityOf(""))
+	}
+	for _, typ := range slices.Sorted(maps.Keys(p.ConditionSeverity)) {
+		i18n.Printf("   🚦 Severity of %s: %s\n", typ, p.ConditionSeverity[typ])
+	}
+}
+
+// parseAlertPolicy parses the --cooldown, --rearm-band, --repeat-every and --severity flag values
+func parseAlertPolicy(cooldown, rearmBand, repeatEvery, severity string) (config.AlertPolicy, error) {
+	var p config.AlertPolicy
+	var err error
+	if cooldown != "" {
+		if p.Cooldown, err = config.ParseDuration(cooldown); err != nil {
+			return p, fmt.Errorf("invalid --cooldown: %w", err)
+		}
+	}
+	if rearmBand != "" {
+		band, err := config.ParseBand(rearmBand)
+		if err != nil {
+			return p, fmt.Errorf("invalid --rearm-band: %w", err)
+		}
+		p.RearmBand = &band
+	}
+	if repeatEvery != "" {
+		if p.RepeatEvery, err = config.ParseDuration(repeatEvery); err != nil {
+			return p, fmt.Errorf("invalid --repeat-every: %w", err)
+		}
+	}
+	if severity != "" {
+		if p.Severity, err = config.ParseSeverity(severity); err != nil {
+			return p, fmt.Errorf("invalid --severity: %w", err)
+		}
+	}
+	return p, nil
 }
 
 func runConfigAdd(args []string) {
 	fs := flag.NewFlagSet("config add", flag.ExitOnError)
 
+	id := fs.String("id", "", "Rule ID to update (optional, a new rule is added otherwise)")
 	symbol := fs.String("symbol", "", "Stock symbol (required)")
 	market := fs.String("market", "US", "Market type (US, CN, TW, CRYPTO, FOREX)")
 	name := fs.String("name", "", "Display name (optional)")
@@ -87,40 +306,103 @@ func runConfigAdd(args []string) {
 	priceBelow := fs.Float64("price-below", 0, "Alert when price is below this value")
 	changeAbove := fs.Float64("change-above", 0, "Alert when percent change is above this value")
 	changeBelow := fs.Float64("change-below", 0, "Alert when percent change is below this value")
+	limitHit := fs.Bool("limit-hit", false, "Alert on limit-up/limit-down (CN A-shares)")
+	limitNear := fs.Float64("limit-near", 0, "Alert when within this percent of a limit band (CN A-shares)")
+	gainAbove := fs.Float64("gain-above", 0, "Alert when the holding's gain vs cost basis is above this percent (take-profit)")
+	lossBelow := fs.Float64("loss-below", 0, "Alert when the holding's gain vs cost basis is below this percent (stop-loss, use negative value)")
+	plAbove := fs.Float64("pl-above", 0, "Alert when the holding's P/L is above this amount")
+	plBelow := fs.Float64("pl-below", 0, "Alert when the holding's P/L is below this amount (use negative value for a loss)")
+	trailingPct := fs.Float64("trailing-stop-pct", 0, "Alert when price falls this percent from its peak since the holding was opened or the rule created")
+	trailingAmount := fs.Float64("trailing-stop-amount", 0, "Alert when price falls this amount from its peak")
+	gapAbove := fs.Float64("gap-above", 0, "Alert when the open gaps up by more than this percent vs the previous close")
+	gapBelow := fs.Float64("gap-below", 0, "Alert when the open gaps down by more than this percent (use negative value)")
+	newDayHighAfter := fs.String("new-day-high-after", "", "Alert on a new intraday high once the session is this old, e.g. 30m")
+	newDayLowAfter := fs.String("new-day-low-after", "", "Alert on a new intraday low once the session is this old, e.g. 30m")
+	breakPrevHigh := fs.Bool("break-prev-high", false, "Alert when price breaks above the previous day's high")
+	breakPrevLow := fs.Bool("break-prev-low", false, "Alert when price breaks below the previous day's low")
+	newHighDays := fs.Int("new-high-days", 0, "Alert when price exceeds the high of the last N days")
+	newLowDays := fs.Int("new-low-days", 0, "Alert when price falls below the low of the last N days")
+	changeDays := fs.Int("change-days", 0, "Measure --period-above/--period-below over the last N trading days")
+	changeSince := fs.String("change-since", "", "Measure --period-above/--period-below since this date (YYYY-MM-DD)")
+	changeFromHigh := fs.Int("change-from-high", 0, "Measure --period-above/--period-below from the high of the last N trading days, e.g. 252 for 52 weeks")
+	changeFromLow := fs.Int("change-from-low", 0, "Measure --period-above/--period-below from the low of the last N trading days")
+	periodAbove := fs.Float64("period-above", 0, "Alert when the change over the period is above this percent")
+	periodBelow := fs.Float64("period-below", 0, "Alert when the change over the period is below this percent (use negative value)")
+	when := fs.String("when", "", "Alert when this expression holds, e.g. \"price > sma(50) && rsi(14) < 30\"")
+	crossPriceSMA := fs.String("cross-price-sma", "", "Alert when price crosses these SMAs, e.g. 50,200")
+	crossSMA := fs.String("cross-sma", "", "Alert on golden/death crosses of a fast and slow SMA, e.g. 50,200")
+	crossMACD := fs.Bool("cross-macd", false, "Alert when MACD crosses its signal line")
+	crossRSI := fs.Int("cross-rsi", 0, "Alert when RSI of this period enters or leaves overbought/oversold")
+	crossResolution := fs.String("cross-resolution", "", "Bar size for crossovers: D (default), 60, 30, 15, 5 or 1 minutes")
+	pairSymbol := fs.String("pair", "", "Compare with this second symbol (leg B), e.g. 9988.HK")
+	pairMarket := fs.String("pair-market", "", "Market of the second symbol")
+	pairFormula := fs.String("pair-formula", "", "Spread formula: ratio (default), diff or spread_pct")
+	pairMultiplier := fs.Float64("pair-multiplier", 0, "Units of the second symbol per unit of the first, e.g. 8")
+	pairFX := fs.String("pair-fx", "", "FX symbol converting the second symbol into the first's currency, e.g. HKDUSD=X")
+	pairAbove := fs.Float64("pair-above", 0, "Alert when the spread is above this value")
+	pairBelow := fs.Float64("pair-below", 0, "Alert when the spread is below this value")
+	pairZScore := fs.Float64("pair-zscore", 0, "Alert when the spread is this many standard deviations from its rolling mean")
+	pairWindow := fs.Int("pair-window", 0, "Days in the spread's rolling mean (default 20)")
+	activeDays := fs.String("active-days", "", "Only evaluate on these days, e.g. mon,tue,wed,thu,fri")
+	activeTimes := fs.String("active-times", "", "Only evaluate in these time ranges of the market's timezone, e.g. 09:30-10:00,15:00-16:00")
+	activeFirst := fs.String("active-first", "", "Only evaluate this long after the session opens, e.g. 30m")
+	cooldown := fs.String("cooldown", "", "Minimum time between alerts for a condition, e.g. 30m")
+	rearmBand := fs.String("rearm-band", "", "Re-arm only after moving back past the threshold by this much, e.g. 2% or 0.5")
+	repeatEvery := fs.String("repeat-every", "", "Repeat the alert while a condition stays met, e.g. 1h")
+	severity := fs.String("severity", "", "Alert severity: info, warning or critical (default info)")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: stock-ping config add [options]\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
 		fs.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		i18n.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol AAPL --price-above 200\n")
 		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol 600519.SS --market CN --name 茅台 --price-below 1400\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol 000001.SZ --market CN --limit-hit --limit-near 1\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol AAPL --when \"price > sma(50) && rsi(14) < 30 || change < -3\"\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol AAPL --gap-above 2 --gap-below -2 --new-high-days 20\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol AAPL --change-days 5 --period-below -10\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol NVDA --change-from-high 252 --period-below -25\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol AAPL --cross-sma 50,200 --cross-rsi 14\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol BABA --pair 9988.HK --pair-market HK --pair-multiplier 8 --pair-fx HKDUSD=X --pair-formula spread_pct --pair-zscore 2\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol TSLA --cross-macd --cross-resolution 15\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol NVDA --gain-above 20 --loss-below -8 --severity critical\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol NVDA --trailing-stop-pct 10\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol TSLA --price-below 180 --rearm-band 2%% --cooldown 30m\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --symbol AAPL --new-day-high-after 5m --active-first 30m\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config add --id aapl-1 --symbol AAPL --price-above 210\n")
 	}
 
 	fs.Parse(args)
 
 	if *symbol == "" {
-		fmt.Fprintf(os.Stderr, "Error: --symbol is required\n\n")
+		i18n.Fprintf(os.Stderr, "Error: --symbol is required\n\n")
 		fs.Usage()
 		os.Exit(1)
 	}
 
-	// Check that at least one condition is set
-	// if *priceAbove == 0 && *priceBelow == 0 && *changeAbove == 0 && *changeBelow == 0 {
-	// 	fmt.Fprintf(os.Stderr, "Error: At least one condition is required\n")
-	// 	fmt.Fprintf(os.Stderr, "Use --price-above, --price-below, --change-above, or --change-below\n")
-	// 	os.Exit(1)
-	// }
-
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
+	if *id != "" {
+		existing := cfg.GetRule(*id)
+		if existing == nil {
+			i18n.Fprintf(os.Stderr, "Rule %s not found\n", *id)
+			os.Exit(1)
+		}
+		if existing.Symbol != *symbol {
+			i18n.Fprintf(os.Stderr, "Rule %s belongs to %s, not %s\n", *id, existing.Symbol, *symbol)
+			os.Exit(1)
+		}
+	}
+
 	// Create rule
 	rule := config.Rule{
+		ID:     *id,
 		Symbol: *symbol,
 		Market: *market,
 		Name:   *name,
@@ -138,34 +420,286 @@ func runConfigAdd(args []string) {
 	if *changeBelow != 0 {
 		rule.ChangeBelow = changeBelow
 	}
+	if *limitHit {
+		rule.LimitHit = true
+	}
+	if *limitNear != 0 {
+		rule.LimitNear = limitNear
+	}
+	if *gainAbove != 0 {
+		rule.GainAbove = gainAbove
+	}
+	if *lossBelow != 0 {
+		rule.LossBelow = lossBelow
+	}
+	if *plAbove != 0 {
+		rule.PLAbove = plAbove
+	}
+	if *plBelow != 0 {
+		rule.PLBelow = plBelow
+	}
+	if *trailingPct != 0 {
+		rule.TrailingStopPct = trailingPct
+	}
+	if *trailingAmount != 0 {
+		rule.TrailingStopAmount = trailingAmount
+	}
+	if *gapAbove != 0 {
+		rule.GapAbove = gapAbove
+	}
+	if *gapBelow != 0 {
+		rule.GapBelow = gapBelow
+	}
+	if *newDayHighAfter != "" {
+		after, err := config.ParseDuration(*newDayHighAfter)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --new-day-high-after: %v\n", err)
+			os.Exit(1)
+		}
+		rule.NewDayHighAfter = &after
+	}
+	if *newDayLowAfter != "" {
+		after, err := config.ParseDuration(*newDayLowAfter)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --new-day-low-after: %v\n", err)
+			os.Exit(1)
+		}
+		rule.NewDayLowAfter = &after
+	}
+	rule.BreakPrevHigh = *breakPrevHigh
+	rule.BreakPrevLow = *breakPrevLow
+	if *newHighDays < 0 || *newLowDays < 0 {
+		i18n.Fprintf(os.Stderr, "Error: --new-high-days and --new-low-days must be positive\n")
+		os.Exit(1)
+	}
+	rule.NewHighDays = *newHighDays
+	rule.NewLowDays = *newLowDays
+	if *changeDays != 0 || *changeSince != "" || *changeFromHigh != 0 || *changeFromLow != 0 || *periodAbove != 0 || *periodBelow != 0 {
+		pc := config.PeriodChange{
+			Days:     *changeDays,
+			Since:    *changeSince,
+			FromHigh: *changeFromHigh,
+			FromLow:  *changeFromLow,
+		}
+		if *periodAbove != 0 {
+			pc.Above = periodAbove
+		}
+		if *periodBelow != 0 {
+			pc.Below = periodBelow
+		}
+		if err := pc.Validate(); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid change: %v\n", err)
+			os.Exit(1)
+		}
+		rule.Changes = []config.PeriodChange{pc}
+	}
+	if *when != "" {
+		rule.When = *when
+		if err := rule.Compile(); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --when expression: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *crossPriceSMA != "" || *crossSMA != "" || *crossMACD || *crossRSI != 0 {
+		x := &config.Crossovers{
+			Resolution: *crossResolution,
+			MACDSignal: *crossMACD,
+			RSI:        *crossRSI,
+		}
+		if x.PriceSMA, err = parseIntList(*crossPriceSMA); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --cross-price-sma: %v\n", err)
+			os.Exit(1)
+		}
+		if x.SMACross, err = parseIntList(*crossSMA); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --cross-sma: %v\n", err)
+			os.Exit(1)
+		}
+		if err := x.Validate(); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid crossovers: %v\n", err)
+			os.Exit(1)
+		}
+		rule.Crossovers = x
+	}
+
+	if *pairSymbol != "" {
+		p := &config.Pair{
+			Symbol:     *pairSymbol,
+			Market:     *pairMarket,
+			Formula:    *pairFormula,
+			Multiplier: *pairMultiplier,
+			FX:         *pairFX,
+			ZScore:     *pairZScore,
+			Window:     *pairWindow,
+		}
+		if *pairAbove != 0 {
+			p.Above = pairAbove
+		}
+		if *pairBelow != 0 {
+			p.Below = pairBelow
+		}
+		if err := p.Validate(); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid pair: %v\n", err)
+			os.Exit(1)
+		}
+		rule.Pair = p
+	}
+
+	if *activeDays != "" || *activeTimes != "" || *activeFirst != "" {
+		s := &config.Schedule{
+			Days:  splitList(*activeDays),
+			Times: splitList(*activeTimes),
+		}
+		if *activeFirst != "" {
+			if s.First, err = config.ParseDuration(*activeFirst); err != nil {
+				i18n.Fprintf(os.Stderr, "Error: invalid --active-first: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := s.Validate(); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid active schedule: %v\n", err)
+			os.Exit(1)
+		}
+		rule.Active = s
+	}
+
+	if rule.AlertPolicy, err = parseAlertPolicy(*cooldown, *rearmBand, *repeatEvery, *severity); err != nil {
+		i18n.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !rule.HasConditions() {
+		i18n.Fprintf(os.Stderr, "Error: at least one condition is required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
 
 	// Add rule
-	cfg.AddRule(rule)
+	ruleID, err := cfg.AddRule(rule)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error adding rule: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Save config
 	if err := cfg.Save(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error saving config: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("✅ Added rule for %s\n", *symbol)
+	if *id != "" {
+		i18n.Printf("✅ Updated rule %s for %s\n", ruleID, *symbol)
+	} else {
+		i18n.Printf("✅ Added rule %s for %s\n", ruleID, *symbol)
+	}
+}
+
+func runConfigPortfolio(args []string) {
+	fs := flag.NewFlagSet("config portfolio", flag.ExitOnError)
+
+	id := fs.String("id", "", "Portfolio rule ID to update (optional, a new rule is added otherwise)")
+	name := fs.String("name", "", "Display name (optional)")
+	valueAbove := fs.Float64("value-above", 0, "Alert when the total market value is above this amount")
+	valueBelow := fs.Float64("value-below", 0, "Alert when the total market value is below this amount")
+	plAbove := fs.Float64("pl-above", 0, "Alert when the total P/L is above this amount")
+	plBelow := fs.Float64("pl-below", 0, "Alert when the total P/L is below this amount (use negative value for a loss)")
+	plPctAbove := fs.Float64("pl-pct-above", 0, "Alert when the total P/L vs cost is above this percent")
+	plPctBelow := fs.Float64("pl-pct-below", 0, "Alert when the total P/L vs cost is below this percent (use negative value)")
+	dayChangeAbove := fs.Float64("day-change-above", 0, "Alert when today's change of the portfolio is above this percent")
+	dayChangeBelow := fs.Float64("day-change-below", 0, "Alert when today's change of the portfolio is below this percent (use negative value)")
+	cooldown := fs.String("cooldown", "", "Minimum time between alerts for a condition, e.g. 30m")
+	rearmBand := fs.String("rearm-band", "", "Re-arm only after moving back past the threshold by this much, e.g. 2% or 0.5")
+	repeatEvery := fs.String("repeat-every", "", "Repeat the alert while a condition stays met, e.g. 1h")
+	severity := fs.String("severity", "", "Alert severity: info, warning or critical (default info)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stock-ping config portfolio [options]\n\n")
+		i18n.Fprintf(os.Stderr, "Alerts on the holdings as a whole, checked after every refresh.\n\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		i18n.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config portfolio --name 回撤保护 --pl-pct-below -5\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config portfolio --day-change-above 2 --day-change-below -2\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping config portfolio --value-above 100000\n")
+	}
+
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *id != "" && cfg.GetPortfolioRule(*id) == nil {
+		i18n.Fprintf(os.Stderr, "Portfolio rule %s not found\n", *id)
+		os.Exit(1)
+	}
+
+	rule := config.PortfolioRule{
+		ID:   *id,
+		Name: *name,
+	}
+	if *valueAbove != 0 {
+		rule.ValueAbove = valueAbove
+	}
+	if *valueBelow != 0 {
+		rule.ValueBelow = valueBelow
+	}
+	if *plAbove != 0 {
+		rule.PLAbove = plAbove
+	}
+	if *plBelow != 0 {
+		rule.PLBelow = plBelow
+	}
+	if *plPctAbove != 0 {
+		rule.PLPctAbove = plPctAbove
+	}
+	if *plPctBelow != 0 {
+		rule.PLPctBelow = plPctBelow
+	}
+	if *dayChangeAbove != 0 {
+		rule.DayChangeAbove = dayChangeAbove
+	}
+	if *dayChangeBelow != 0 {
+		rule.DayChangeBelow = dayChangeBelow
+	}
+	if rule.AlertPolicy, err = parseAlertPolicy(*cooldown, *rearmBand, *repeatEvery, *severity); err != nil {
+		i18n.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ruleID := cfg.AddPortfolioRule(rule)
+
+	if err := cfg.Save(); err != nil {
+		i18n.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *id != "" {
+		i18n.Printf("✅ Updated portfolio rule %s\n", ruleID)
+	} else {
+		i18n.Printf("✅ Added portfolio rule %s\n", ruleID)
+	}
 }
 
 func runConfigRemove(args []string) {
 	fs := flag.NewFlagSet("config remove", flag.ExitOnError)
 
-	symbol := fs.String("symbol", "", "Stock symbol to remove (required)")
+	id := fs.String("id", "", "Rule ID to remove (see: stock-ping config list)")
+	symbol := fs.String("symbol", "", "Remove every rule for this symbol instead")
 
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: stock-ping config remove --symbol <SYMBOL>\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
+		fmt.Fprintf(os.Stderr, "Usage: stock-ping config remove --id <RULE_ID>\n")
+		fmt.Fprintf(os.Stderr, "       stock-ping config remove --symbol <SYMBOL>\n\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
 		fs.PrintDefaults()
 	}
 
 	fs.Parse(args)
 
-	if *symbol == "" {
-		fmt.Fprintf(os.Stderr, "Error: --symbol is required\n\n")
+	if *id == "" && *symbol == "" {
+		i18n.Fprintf(os.Stderr, "Error: --id or --symbol is required\n\n")
 		fs.Usage()
 		os.Exit(1)
 	}
@@ -173,26 +707,139 @@ func runConfigRemove(args []string) {
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Remove rule
-	if !cfg.RemoveRule(*symbol) {
-		fmt.Fprintf(os.Stderr, "Rule for %s not found\n", *symbol)
+	// Remove rules
+	var removed []string
+	if *id != "" {
+		if cfg.RemoveRule(*id) {
+			removed = append(removed, *id)
+		}
+	} else {
+		for _, r := range cfg.RulesForSymbol(*symbol) {
+			removed = append(removed, r.ID)
+		}
+		for _, ruleID := range removed {
+			cfg.RemoveRule(ruleID)
+		}
+	}
+
+	if len(removed) == 0 {
+		if *id != "" {
+			i18n.Fprintf(os.Stderr, "Rule %s not found\n", *id)
+		} else {
+			i18n.Fprintf(os.Stderr, "No rules for %s found\n", *symbol)
+		}
 		os.Exit(1)
 	}
 
 	// Save config
 	if err := cfg.Save(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error saving config: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("✅ Removed rule for %s\n", *symbol)
+	for _, ruleID := range removed {
+		i18n.Printf("✅ Removed rule %s\n", ruleID)
+	}
+}
+
+func runConfigSetEnabled(args []string, enabled bool) {
+	name := "disable"
+	if enabled {
+		name = "enable"
+	}
+	fs := flag.NewFlagSet("config "+name, flag.ExitOnError)
+
+	id := fs.String("id", "", "Rule ID (required, see: stock-ping config list)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stock-ping config %s --id <RULE_ID>\n\n", name)
+		i18n.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	fs.Parse(args)
+
+	if *id == "" {
+		i18n.Fprintf(os.Stderr, "Error: --id is required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	var enabledField **bool
+	if r := cfg.GetRule(*id); r != nil {
+		enabledField = &r.Enabled
+	} else if r := cfg.GetPortfolioRule(*id); r != nil {
+		enabledField = &r.Enabled
+	} else {
+		i18n.Fprintf(os.Stderr, "Rule %s not found\n", *id)
+		os.Exit(1)
+	}
+
+	// Omit the field when enabled, since that is the default
+	if enabled {
+		*enabledField = nil
+	} else {
+		*enabledField = &enabled
+	}
+
+	// Save config
+	if err := cfg.Save(); err != nil {
+		i18n.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if enabled {
+		i18n.Printf("✅ Rule %s enabled\n", *id)
+		// Also lift a snooze or disable set from the dashboard
+		if state := openStateStore(cfg); state != nil {
+			if n, _ := rule.NewTracker(state).Resume([]string{*id}); n > 0 {
+				i18n.Println("   Its snooze or disable from the dashboard was lifted")
+			}
+		}
+	} else {
+		i18n.Printf("✅ Rule %s disabled\n", *id)
+	}
 }
 
 // RunAdd is a top-level alias for RunConfigAdd
 func RunAdd(args []string) {
 	runConfigAdd(args)
 }
+
+// parseIntList parses a comma-separated list of integers such as "50,200"
+func parseIntList(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var list []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %w", part, err)
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(s string) []string {
+	var list []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
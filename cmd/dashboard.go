@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
 	"github.com/congregalis/stock-ping/notify"
 	"github.com/congregalis/stock-ping/stock"
 	"github.com/congregalis/stock-ping/tui"
@@ -18,12 +19,12 @@ func RunDashboard(args []string) {
 	fs := flag.NewFlagSet("dashboard", flag.ExitOnError)
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: stock-ping dashboard\n\n")
-		fmt.Fprintf(os.Stderr, "Launch interactive TUI dashboard for stock monitoring.\n")
-		fmt.Fprintf(os.Stderr, "Supports hot-reload: edit ~/.stock-ping.yaml to update rules.\n\n")
-		fmt.Fprintf(os.Stderr, "Keybindings:\n")
-		fmt.Fprintf(os.Stderr, "  r       Refresh all stocks\n")
-		fmt.Fprintf(os.Stderr, "  q       Quit\n")
-		fmt.Fprintf(os.Stderr, "  ?       Help\n")
+		i18n.Fprintf(os.Stderr, "Launch interactive TUI dashboard for stock monitoring.\n")
+		i18n.Fprintf(os.Stderr, "Supports hot-reload: edit ~/.stock-ping.yaml to update rules.\n\n")
+		i18n.Fprintf(os.Stderr, "Keybindings:\n")
+		i18n.Fprintf(os.Stderr, "  r       Refresh all stocks\n")
+		i18n.Fprintf(os.Stderr, "  q       Quit\n")
+		i18n.Fprintf(os.Stderr, "  ?       Help\n")
 	}
 
 	fs.Parse(args)
@@ -32,19 +33,19 @@ func RunDashboard(args []string) {
 	configPath := config.DefaultConfigPath()
 	cfg, err := config.LoadFrom(configPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
 	if cfg.Finnhub.APIKey == "" {
-		fmt.Fprintf(os.Stderr, "Error: Finnhub API key not configured.\n")
-		fmt.Fprintf(os.Stderr, "Please add your API key to ~/.stock-ping.yaml\n")
+		i18n.Fprintf(os.Stderr, "Error: Finnhub API key not configured.\n")
+		i18n.Fprintf(os.Stderr, "Please add your API key to ~/.stock-ping.yaml\n")
 		os.Exit(1)
 	}
 
 	if len(cfg.Rules) == 0 {
-		fmt.Fprintf(os.Stderr, "No monitoring rules configured.\n")
-		fmt.Fprintf(os.Stderr, "Add rules with: stock-ping config add --symbol AAPL --price-above 200\n")
+		i18n.Fprintf(os.Stderr, "No monitoring rules configured.\n")
+		i18n.Fprintf(os.Stderr, "Add rules with: stock-ping config add --symbol AAPL --price-above 200\n")
 		os.Exit(1)
 	}
 
@@ -53,7 +54,7 @@ func RunDashboard(args []string) {
 	notifier := notify.NewNotifier(cfg.Bark.ServerURL, cfg.Bark.Key)
 
 	// Create TUI model
-	model := tui.NewModel(cfg, stockClient, notifier, configPath)
+	model := tui.NewModel(cfg, stockClient, notifier, openTickStore(cfg), openAlertLog(cfg), openStateStore(cfg), configPath)
 
 	// Create program
 	p := tea.NewProgram(model, tea.WithAltScreen())
@@ -63,7 +64,7 @@ func RunDashboard(args []string) {
 		p.Send(tui.ReloadConfig(newCfg)())
 	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Failed to setup config watcher: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Warning: Failed to setup config watcher: %v\n", err)
 	} else {
 		configWatcher.Start()
 		defer configWatcher.Stop()
@@ -71,7 +72,7 @@ func RunDashboard(args []string) {
 
 	// Run TUI
 	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 		os.Exit(1)
 	}
 }
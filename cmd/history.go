@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/store"
+)
+
+// RunHistory executes the history subcommand
+func RunHistory(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+
+	from := fs.String("from", "", "Start time (YYYY-MM-DD or YYYY-MM-DD HH:MM, default: 24h ago)")
+	to := fs.String("to", "", "End time (YYYY-MM-DD or YYYY-MM-DD HH:MM, default: now)")
+	asJSON := fs.Bool("json", false, "Output ticks as JSON")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stock-ping history [options] <SYMBOL>\n\n")
+		i18n.Fprintf(os.Stderr, "Show quotes recorded by watch and dashboard.\n\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		i18n.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping history AAPL\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping history --from 2024-06-01 --to 2024-06-02 --json BTC-USD\n")
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	symbol := fs.Arg(0)
+
+	end := time.Now()
+	start := end.Add(-24 * time.Hour)
+	var err error
+	if *from != "" {
+		if start, err = parseTimeFlag(*from); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --from: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *to != "" {
+		if end, err = parseTimeFlag(*to); err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --to: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	ticks, err := store.NewTickStore(cfg.History)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error opening tick history: %v\n", err)
+		os.Exit(1)
+	}
+
+	result, err := ticks.Query(symbol, start, end)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error querying tick history: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if result == nil {
+			result = []store.Tick{}
+		}
+		enc.Encode(result)
+		return
+	}
+
+	if len(result) == 0 {
+		i18n.Printf("No ticks recorded for %s between %s and %s.\n",
+			symbol, start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
+		return
+	}
+
+	i18n.Printf("🕒 %s (%d ticks)\n", symbol, len(result))
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	for _, t := range result {
+		i18n.Printf("%s  $%.2f (%+.2f%%)\n", time.Unix(t.Time, 0).Format("2006-01-02 15:04:05"), t.Price, t.PercentChange)
+	}
+}
+
+// parseTimeFlag parses a date or date-time flag in local time
+func parseTimeFlag(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or YYYY-MM-DD HH:MM, got %q", s)
+}
@@ -3,9 +3,11 @@ package cmd
 import (
 	"flag"
 	"fmt"
+	"math"
 	"os"
 
 	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
 	"github.com/congregalis/stock-ping/stock"
 )
 
@@ -31,26 +33,26 @@ func RunHolding(args []string) {
 
 func printHoldingUsage() {
 	fmt.Fprintf(os.Stderr, "Usage: stock-ping holding <command>\n\n")
-	fmt.Fprintf(os.Stderr, "Commands:\n")
-	fmt.Fprintf(os.Stderr, "  list                 List all holdings\n")
-	fmt.Fprintf(os.Stderr, "  add                  Add or update a holding\n")
-	fmt.Fprintf(os.Stderr, "  remove               Remove a holding\n")
+	i18n.Fprintf(os.Stderr, "Commands:\n")
+	i18n.Fprintf(os.Stderr, "  list                 List all holdings\n")
+	i18n.Fprintf(os.Stderr, "  add                  Add or update a holding\n")
+	i18n.Fprintf(os.Stderr, "  remove               Remove a holding\n")
 }
 
 func runHoldingList(args []string) {
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
 	if len(cfg.Holdings) == 0 {
-		fmt.Println("No holdings configured.")
-		fmt.Println("Add holdings with: stock-ping holding add --symbol AAPL --quantity 100 --cost 150.50")
+		i18n.Println("No holdings configured.")
+		i18n.Println("Add holdings with: stock-ping holding add --symbol AAPL --quantity 100 --cost 150.50")
 		return
 	}
 
-	fmt.Printf("📊 Holdings (%d)\n", len(cfg.Holdings))
+	i18n.Printf("📊 Holdings (%d)\n", len(cfg.Holdings))
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	var totalCost float64
@@ -58,14 +60,14 @@ func runHoldingList(args []string) {
 		cost := h.Quantity * h.CostPrice
 		totalCost += cost
 		fmt.Printf("%d. %s\n", i+1, h.Symbol)
-		fmt.Printf("   • 数量: %.2f\n", h.Quantity)
-		fmt.Printf("   • 成本价: $%.2f\n", h.CostPrice)
-		fmt.Printf("   • 持仓成本: $%.2f\n", cost)
+		i18n.Printf("   • Quantity: %.2f\n", h.Quantity)
+		i18n.Printf("   • Cost price: $%.2f\n", h.CostPrice)
+		i18n.Printf("   • Cost basis: $%.2f\n", cost)
 	}
 
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("总持仓成本: $%.2f\n", totalCost)
-	fmt.Printf("Config file: %s\n", config.DefaultConfigPath())
+	i18n.Printf("Total cost basis: $%.2f\n", totalCost)
+	i18n.Printf("Config file: %s\n", config.DefaultConfigPath())
 }
 
 func runHoldingAdd(args []string) {
@@ -74,32 +76,35 @@ func runHoldingAdd(args []string) {
 	symbol := fs.String("symbol", "", "Stock symbol (required)")
 	quantity := fs.Float64("quantity", 0, "Number of shares (required)")
 	costPrice := fs.Float64("cost", 0, "Cost price per share (required)")
+	takeProfit := fs.Float64("take-profit", 0, "Also add a rule alerting at this percent gain vs cost, e.g. 20")
+	stopLoss := fs.Float64("stop-loss", 0, "Also add a rule alerting at this percent loss vs cost, e.g. 8")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: stock-ping holding add [options]\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
 		fs.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		i18n.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  stock-ping holding add --symbol AAPL --quantity 100 --cost 150.50\n")
 		fmt.Fprintf(os.Stderr, "  stock-ping holding add --symbol 600519.SS --quantity 10 --cost 1500\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping holding add --symbol NVDA --quantity 50 --cost 120 --take-profit 20 --stop-loss 8\n")
 	}
 
 	fs.Parse(args)
 
 	if *symbol == "" {
-		fmt.Fprintf(os.Stderr, "Error: --symbol is required\n\n")
+		i18n.Fprintf(os.Stderr, "Error: --symbol is required\n\n")
 		fs.Usage()
 		os.Exit(1)
 	}
 
 	if *quantity <= 0 {
-		fmt.Fprintf(os.Stderr, "Error: --quantity must be greater than 0\n\n")
+		i18n.Fprintf(os.Stderr, "Error: --quantity must be greater than 0\n\n")
 		fs.Usage()
 		os.Exit(1)
 	}
 
 	if *costPrice <= 0 {
-		fmt.Fprintf(os.Stderr, "Error: --cost must be greater than 0\n\n")
+		i18n.Fprintf(os.Stderr, "Error: --cost must be greater than 0\n\n")
 		fs.Usage()
 		os.Exit(1)
 	}
@@ -107,7 +112,7 @@ func runHoldingAdd(args []string) {
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -118,14 +123,14 @@ func runHoldingAdd(args []string) {
 		newTotalQuantity := existing.Quantity + *quantity
 		newAvgCost := (currentTotalCost + newAddCost) / newTotalQuantity
 
-		fmt.Printf("ℹ️  Existing holding found: %.2f shares @ $%.2f\n", existing.Quantity, existing.CostPrice)
-		fmt.Printf("   Adding: %.2f shares @ $%.2f\n", *quantity, *costPrice)
+		i18n.Printf("ℹ️  Existing holding found: %.2f shares @ $%.2f\n", existing.Quantity, existing.CostPrice)
+		i18n.Printf("   Adding: %.2f shares @ $%.2f\n", *quantity, *costPrice)
 
 		// Update variables to new total values
 		*quantity = newTotalQuantity
 		*costPrice = newAvgCost
 
-		fmt.Printf("   New Position: %.2f shares @ $%.2f\n", *quantity, *costPrice)
+		i18n.Printf("   New Position: %.2f shares @ $%.2f\n", *quantity, *costPrice)
 	}
 
 	// Create holding
@@ -138,17 +143,17 @@ func runHoldingAdd(args []string) {
 	// Add holding
 	cfg.AddHolding(holding)
 
-	// Check if rule exists, if not create it
-	if cfg.GetRule(*symbol) == nil {
-		fmt.Printf("ℹ️  No monitoring rule found for %s. Creating one...\n", *symbol)
+	// Check if the symbol is monitored, if not create a rule for it
+	if len(cfg.RulesForSymbol(*symbol)) == 0 {
+		i18n.Printf("ℹ️  No monitoring rule found for %s. Creating one...\n", *symbol)
 
 		name, market, err := stock.FetchSymbolDetails(*symbol)
 		if err != nil {
-			fmt.Printf("⚠️  Failed to fetch symbol details: %v. Using defaults.\n", err)
+			i18n.Printf("⚠️  Failed to fetch symbol details: %v. Using defaults.\n", err)
 			name = *symbol
 			market = stock.MarketUS
 		} else {
-			fmt.Printf("✅ Found details: %s (%s)\n", name, market)
+			i18n.Printf("✅ Found details: %s (%s)\n", name, market)
 		}
 
 		newRule := config.Rule{
@@ -156,22 +161,64 @@ func runHoldingAdd(args []string) {
 			Name:   name,
 			Market: market,
 		}
-		if err := cfg.AddRule(newRule); err != nil {
-			fmt.Printf("⚠️  Failed to add rule: %v\n", err)
+		if _, err := cfg.AddRule(newRule); err != nil {
+			i18n.Printf("⚠️  Failed to add rule: %v\n", err)
+		}
+	}
+
+	// Protective alerts measured against the cost basis. Re-running with new
+	// levels updates the symbol's existing protective rule.
+	if *takeProfit != 0 || *stopLoss != 0 {
+		var protective *config.Rule
+		for _, r := range cfg.RulesForSymbol(*symbol) {
+			if r.GainAbove != nil || r.LossBelow != nil {
+				protective = r
+				break
+			}
+		}
+		if protective != nil {
+			setProtectiveLevels(protective, *takeProfit, *stopLoss)
+			i18n.Printf("✅ Updated take-profit/stop-loss rule %s\n", protective.ID)
+		} else {
+			name, market := cfg.SymbolInfo(*symbol)
+			rule := config.Rule{
+				Symbol: *symbol,
+				Name:   name,
+				Market: market,
+			}
+			setProtectiveLevels(&rule, *takeProfit, *stopLoss)
+			if ruleID, err := cfg.AddRule(rule); err != nil {
+				i18n.Printf("⚠️  Failed to add take-profit/stop-loss rule: %v\n", err)
+			} else {
+				i18n.Printf("✅ Added take-profit/stop-loss rule %s\n", ruleID)
+			}
 		}
 	}
 
 	// Save config
 	if err := cfg.Save(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error saving config: %v\n", err)
 		os.Exit(1)
 	}
 
 	totalCost := *quantity * *costPrice
-	fmt.Printf("✅ Added holding for %s: %.2f shares @ $%.2f (total cost: $%.2f)\n",
+	i18n.Printf("✅ Added holding for %s: %.2f shares @ $%.2f (total cost: $%.2f)\n",
 		*symbol, *quantity, *costPrice, totalCost)
 }
 
+// setProtectiveLevels sets the take-profit and stop-loss percentages that are
+// non-zero on r, keeping the other as is
+func setProtectiveLevels(r *config.Rule, takeProfit, stopLoss float64) {
+	if takeProfit != 0 {
+		gain := math.Abs(takeProfit)
+		r.GainAbove = &gain
+	}
+	if stopLoss != 0 {
+		loss := -math.Abs(stopLoss)
+		r.LossBelow = &loss
+	}
+}
+
 func runHoldingRemove(args []string) {
 	fs := flag.NewFlagSet("holding remove", flag.ExitOnError)
 
@@ -179,14 +226,14 @@ func runHoldingRemove(args []string) {
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: stock-ping holding remove --symbol <SYMBOL>\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
 		fs.PrintDefaults()
 	}
 
 	fs.Parse(args)
 
 	if *symbol == "" {
-		fmt.Fprintf(os.Stderr, "Error: --symbol is required\n\n")
+		i18n.Fprintf(os.Stderr, "Error: --symbol is required\n\n")
 		fs.Usage()
 		os.Exit(1)
 	}
@@ -194,21 +241,21 @@ func runHoldingRemove(args []string) {
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Remove holding
 	if !cfg.RemoveHolding(*symbol) {
-		fmt.Fprintf(os.Stderr, "Holding for %s not found\n", *symbol)
+		i18n.Fprintf(os.Stderr, "Holding for %s not found\n", *symbol)
 		os.Exit(1)
 	}
 
 	// Save config
 	if err := cfg.Save(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error saving config: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("✅ Removed holding for %s\n", *symbol)
+	i18n.Printf("✅ Removed holding for %s\n", *symbol)
 }
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/rule"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// RunRule executes the rule subcommand
+func RunRule(args []string) {
+	if len(args) < 1 {
+		printRuleUsage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "test":
+		runRuleTest(args[1:])
+	default:
+		printRuleUsage()
+		os.Exit(1)
+	}
+}
+
+func printRuleUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: stock-ping rule <command>\n\n")
+	i18n.Fprintf(os.Stderr, "Commands:\n")
+	i18n.Fprintf(os.Stderr, "  test                 Dry-run a symbol's rules against a made-up quote\n")
+}
+
+// runRuleTest evaluates the configured rules of a symbol against a synthetic
+// quote and prints what would fire, without sending anything
+func runRuleTest(args []string) {
+	fs := flag.NewFlagSet("rule test", flag.ExitOnError)
+
+	symbol := fs.String("symbol", "", "Stock symbol whose rules to test (required)")
+	id := fs.String("id", "", "Only test the rule with this ID")
+	quoteFile := fs.String("quote-file", "", "JSON file with the quote, e.g. {\"CurrentPrice\": 205, \"PrevClose\": 211.7}")
+	price := fs.Float64("price", 0, "Current price")
+	change := fs.Float64("change", 0, "Percent change vs the previous close")
+	prevClose := fs.Float64("prev-close", 0, "Previous close (derived from --price and --change if omitted)")
+	open := fs.Float64("open", 0, "Open price of the day")
+	high := fs.Float64("high", 0, "High price of the day")
+	low := fs.Float64("low", 0, "Low price of the day")
+	volume := fs.Float64("volume", 0, "Volume")
+	limitUp := fs.Float64("limit-up", 0, "Limit-up price (涨停价), A-shares only")
+	limitDown := fs.Float64("limit-down", 0, "Limit-down price (跌停价), A-shares only")
+	at := fs.String("at", "", "Time of the quote (YYYY-MM-DD HH:MM, default: now), for active schedules and session times")
+	offline := fs.Bool("offline", false, "Do not fetch candles or pair legs")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stock-ping rule test [options]\n\n")
+		i18n.Fprintf(os.Stderr, "Run a symbol's rules against a made-up quote and show what would fire. Nothing is sent\n")
+		i18n.Fprintf(os.Stderr, "and alert state is not touched.\n\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		i18n.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping rule test --symbol AAPL --price 205 --change -3.1\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping rule test --symbol AAPL --id aapl-2 --price 148 --at \"2024-06-03 09:45\"\n")
+		fmt.Fprintf(os.Stderr, "  stock-ping rule test --symbol AAPL --quote-file quote.json --offline\n")
+	}
+
+	fs.Parse(args)
+
+	if *symbol == "" {
+		i18n.Fprintf(os.Stderr, "Error: --symbol is required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Flags override the quote file
+	quote := &stock.Quote{}
+	if *quoteFile != "" {
+		data, err := os.ReadFile(*quoteFile)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Error reading quote file: %v\n", err)
+			os.Exit(1)
+		}
+		if err := json.Unmarshal(data, quote); err != nil {
+			i18n.Fprintf(os.Stderr, "Error parsing quote file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	changeSet := quote.PercentChange != 0
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "price":
+			quote.CurrentPrice = *price
+		case "change":
+			quote.PercentChange = *change
+			changeSet = true
+		case "prev-close":
+			quote.PrevClose = *prevClose
+		case "open":
+			quote.Open = *open
+		case "high":
+			quote.High = *high
+		case "low":
+			quote.Low = *low
+		case "volume":
+			quote.Volume = *volume
+		case "limit-up":
+			quote.LimitUp = *limitUp
+		case "limit-down":
+			quote.LimitDown = *limitDown
+		}
+	})
+	if quote.CurrentPrice <= 0 {
+		i18n.Fprintf(os.Stderr, "Error: a positive --price (or CurrentPrice in --quote-file) is required\n")
+		os.Exit(1)
+	}
+	completeQuote(quote, changeSet)
+	quote.Symbol = *symbol
+
+	now := time.Now()
+	if *at != "" {
+		t, err := parseTimeFlag(*at)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Error: invalid --at: %v\n", err)
+			os.Exit(1)
+		}
+		now = t
+	}
+	quote.Timestamp = now.Unix()
+
+	cfg, err := config.Load()
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	var rules []*config.Rule
+	for _, r := range cfg.RulesForSymbol(*symbol) {
+		if *id == "" || r.ID == *id {
+			rules = append(rules, r)
+		}
+	}
+	if len(rules) == 0 {
+		i18n.Fprintf(os.Stderr, "Error: no rules found for %s\n", *symbol)
+		os.Exit(1)
+	}
+
+	_, market := cfg.SymbolInfo(*symbol)
+	in := rule.Input{
+		Quote:   quote,
+		Holding: cfg.GetHolding(*symbol),
+		Market:  market,
+	}
+	if !*offline {
+		fetchRuleTestData(stock.NewClient(cfg.Finnhub.APIKey), &in, *symbol, market, rules)
+	}
+
+	i18n.Printf("🧪 %s $%.2f (%+.2f%%) prev close $%.2f\n", *symbol, quote.CurrentPrice, quote.PercentChange, quote.PrevClose)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	// A tracker without a store starts from a clean state and saves nothing
+	tracker := rule.NewTracker(nil)
+	evaluator := rule.NewEvaluator()
+	for _, r := range rules {
+		status := ""
+		if !r.IsEnabled() {
+			status = i18n.T(" (disabled)")
+		}
+		fmt.Printf("[%s]%s\n", r.ID, status)
+
+		o, _ := tracker.Evaluate(evaluator, r, in, now)
+		switch {
+		case o.Inactive:
+			i18n.Println("   🕒 Outside the rule's active schedule, not evaluated")
+			continue
+		case !o.Triggered():
+			i18n.Println("   ✓ No condition met")
+			continue
+		}
+
+		for _, c := range o.Conditions {
+			if icon := rule.SeverityIcon(c.Severity); icon != "" {
+				fmt.Printf("   🔔 %s %s\n", icon, c.Text)
+			} else {
+				fmt.Printf("   🔔 %s\n", c.Text)
+			}
+		}
+		if o.TrailingStop > 0 {
+			i18n.Printf("   🛑 Trailing stop $%.2f (peak $%.2f)\n", o.TrailingStop, o.TrailingPeak)
+		}
+
+		title, body := o.FormatNotification()
+		i18n.Printf("   📱 %s notification (not sent):\n", o.Severity())
+		fmt.Printf("      %s\n", title)
+		for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+			fmt.Printf("      %s\n", line)
+		}
+		if cfg.Digest.Batches(o.Severity()) {
+			i18n.Println("   🗂  Batched into the digest instead of sent on its own")
+		}
+	}
+}
+
+// completeQuote derives the previous close, change and percent change from
+// whichever of them were given
+func completeQuote(q *stock.Quote, changeSet bool) {
+	if q.PrevClose <= 0 && changeSet && q.PercentChange > -100 {
+		q.PrevClose = q.CurrentPrice / (1 + q.PercentChange/100)
+	}
+	if q.PrevClose <= 0 {
+		q.PrevClose = q.CurrentPrice
+	}
+	q.Change = q.CurrentPrice - q.PrevClose
+	if !changeSet {
+		q.PercentChange = q.Change / q.PrevClose * 100
+	}
+	if q.Open <= 0 {
+		q.Open = q.PrevClose
+	}
+	if q.High <= 0 {
+		q.High = max(q.Open, q.CurrentPrice)
+	}
+	if q.Low <= 0 {
+		q.Low = min(q.Open, q.CurrentPrice)
+	}
+}
+
+// fetchRuleTestData fills in the candles and pair legs the rules need. Failures
+// are reported and leave the data out, so the conditions using it do not fire.
+func fetchRuleTestData(client *stock.Client, in *rule.Input, symbol, market string, rules []*config.Rule) {
+	candles := stock.NewCandleCache(client, time.Hour)
+	for res, bars := range rule.CandleNeeds(rules, in.Holding) {
+		history, err := candles.Get(symbol, market, res, bars)
+		if err != nil {
+			i18n.Printf("⚠️  Failed to fetch candles (%s): %v\n", res, err)
+			continue
+		}
+		if res == "D" {
+			in.Candles = history
+		} else {
+			if in.Intraday == nil {
+				in.Intraday = make(map[string]*stock.Candle)
+			}
+			in.Intraday[res] = history
+		}
+	}
+
+	for _, r := range rules {
+		if !r.IsEnabled() || r.Pair == nil {
+			continue
+		}
+		for _, l := range r.Pair.Legs() {
+			if in.Legs == nil {
+				in.Legs = make(map[string]*rule.Leg)
+			}
+			if _, ok := in.Legs[l.Symbol]; ok {
+				continue
+			}
+			legQuote, err := client.GetQuote(l.Symbol, l.Market)
+			if err != nil {
+				i18n.Printf("⚠️  Failed to fetch pair leg %s: %v\n", l.Symbol, err)
+				continue
+			}
+			leg := &rule.Leg{Quote: legQuote}
+			if lookback := rule.PairLookback(r); lookback > 0 {
+				if leg.Candles, err = candles.Daily(l.Symbol, l.Market, lookback); err != nil {
+					i18n.Printf("⚠️  Failed to fetch candles for %s: %v\n", l.Symbol, err)
+				}
+			}
+			in.Legs[l.Symbol] = leg
+		}
+	}
+}
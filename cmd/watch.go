@@ -5,19 +5,19 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
 	"github.com/congregalis/stock-ping/notify"
 	"github.com/congregalis/stock-ping/rule"
 	"github.com/congregalis/stock-ping/stock"
+	"github.com/congregalis/stock-ping/store"
 )
 
-// triggeredState tracks which conditions have been triggered for each symbol
-// map[symbol]map[conditionKey]bool - true means condition was triggered in last check
-var triggeredState = make(map[string]map[string]bool)
-
 // US Eastern timezone for market hours check
 var easternTZ *time.Location
 
@@ -74,52 +74,67 @@ func formatDuration(d time.Duration) string {
 	hours := int(d.Hours())
 	minutes := int(d.Minutes()) % 60
 	if hours > 0 {
-		return fmt.Sprintf("%d小时%d分钟", hours, minutes)
+		return i18n.T("%dh %dm", hours, minutes)
 	}
-	return fmt.Sprintf("%d分钟", minutes)
+	return i18n.T("%dm", minutes)
 }
 
 // RunWatch executes the watch subcommand
 func RunWatch(args []string) {
 	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	severity := fs.String("severity", "info", "Only print alerts of at least this severity: info, warning or critical")
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: stock-ping watch\n\n")
-		fmt.Fprintf(os.Stderr, "Continuously monitor stocks based on configured rules.\n")
-		fmt.Fprintf(os.Stderr, "Press Ctrl+C to stop.\n")
+		fmt.Fprintf(os.Stderr, "Usage: stock-ping watch [options]\n\n")
+		i18n.Fprintf(os.Stderr, "Continuously monitor stocks based on configured rules.\n")
+		i18n.Fprintf(os.Stderr, "Press Ctrl+C to stop.\n\n")
+		i18n.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
 	}
 
 	fs.Parse(args)
 
+	minSeverity, err := config.ParseSeverity(*severity)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		i18n.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
 	if cfg.Finnhub.APIKey == "" {
-		fmt.Fprintf(os.Stderr, "Error: Finnhub API key not configured.\n")
-		fmt.Fprintf(os.Stderr, "Please add your API key to ~/.stock-ping.yaml\n")
+		i18n.Fprintf(os.Stderr, "Error: Finnhub API key not configured.\n")
+		i18n.Fprintf(os.Stderr, "Please add your API key to ~/.stock-ping.yaml\n")
 		os.Exit(1)
 	}
 
-	if len(cfg.Rules) == 0 {
-		fmt.Fprintf(os.Stderr, "No monitoring rules configured.\n")
-		fmt.Fprintf(os.Stderr, "Add rules with: stock-ping config add --symbol AAPL --price-above 200\n")
+	if len(cfg.Rules) == 0 && len(cfg.PortfolioRules) == 0 {
+		i18n.Fprintf(os.Stderr, "No monitoring rules configured.\n")
+		i18n.Fprintf(os.Stderr, "Add rules with: stock-ping config add --symbol AAPL --price-above 200\n")
 		os.Exit(1)
 	}
 
 	// Create clients
 	stockClient := stock.NewClient(cfg.Finnhub.APIKey)
 	notifier := notify.NewNotifier(cfg.Bark.ServerURL, cfg.Bark.Key)
+	alerts := notify.NewAlerter(notifier, openAlertLog(cfg))
 	evaluator := rule.NewEvaluator()
+	tracker := rule.NewTracker(openStateStore(cfg))
+	tracker.SetQuietHours(cfg.QuietHours)
+	ticks := openTickStore(cfg)
+	candles := stock.NewCandleCache(stockClient, 15*time.Minute)
+	feed := rule.NewFeedMonitor(cfg.FeedHealth, cfg.QuietHours)
 
 	// Print startup message
-	fmt.Printf("🔔 Stock Monitor Started (interval: %ds)\n", cfg.Interval)
+	i18n.Printf("🔔 Stock Monitor Started (interval: %ds)\n", cfg.Interval)
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	if !notifier.IsConfigured() {
-		fmt.Println("⚠️  Warning: Bark not configured, notifications disabled")
+		i18n.Println("⚠️  Warning: Bark not configured, notifications disabled")
 	}
 
 	// Setup signal handling for graceful shutdown
@@ -127,25 +142,26 @@ func RunWatch(args []string) {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	// Run first check immediately (regardless of market status)
-	checkRules(cfg, stockClient, notifier, evaluator)
+	checkRules(cfg, stockClient, candles, alerts, evaluator, tracker, feed, ticks, minSeverity)
 
 	// Check if market is currently open
 	if !isMarketOpen() {
 		nextOpen := getNextMarketOpen()
 		waitDuration := time.Until(nextOpen)
-		fmt.Printf("\n💤 美股休市中，将在 %s 后自动恢复监控\n", formatDuration(waitDuration))
-		fmt.Printf("   下次开盘时间: %s (美东时间)\n", nextOpen.Format("01-02 15:04 Mon"))
-		fmt.Println("   程序将继续运行，等待开盘...")
+		i18n.Printf("\n💤 US market closed, resuming in %s\n", formatDuration(waitDuration))
+		i18n.Printf("   Next open: %s (US Eastern)\n", nextOpen.Format("01-02 15:04 Mon"))
+		i18n.Println("   Waiting for the open...")
 
 		// Wait for market to open
 		waitTimer := time.NewTimer(waitDuration)
 		select {
 		case <-waitTimer.C:
-			fmt.Println("\n🔔 美股开盘，恢复监控!")
+			i18n.Println("\n🔔 US market open, monitoring resumed!")
 			fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 		case <-sigChan:
 			waitTimer.Stop()
-			fmt.Println("\n👋 Shutting down...")
+			i18n.Println("\n👋 Shutting down...")
+			flushDigest(cfg, alerts, true)
 			return
 		}
 	}
@@ -162,115 +178,348 @@ func RunWatch(args []string) {
 			if !isMarketOpen() {
 				nextOpen := getNextMarketOpen()
 				waitDuration := time.Until(nextOpen)
-				fmt.Printf("\n💤 美股收盘，将在 %s 后自动恢复监控\n", formatDuration(waitDuration))
-				fmt.Printf("   下次开盘时间: %s (美东时间)\n", nextOpen.Format("01-02 15:04 Mon"))
+				i18n.Printf("\n💤 US market closed for the day, resuming in %s\n", formatDuration(waitDuration))
+				i18n.Printf("   Next open: %s (US Eastern)\n", nextOpen.Format("01-02 15:04 Mon"))
 
 				// Stop current ticker and wait for market open
 				ticker.Stop()
 				waitTimer := time.NewTimer(waitDuration)
 				select {
 				case <-waitTimer.C:
-					fmt.Println("\n🔔 美股开盘，恢复监控!")
+					i18n.Println("\n🔔 US market open, monitoring resumed!")
 					fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 					ticker.Reset(time.Duration(cfg.Interval) * time.Second)
 				case <-sigChan:
 					waitTimer.Stop()
-					fmt.Println("\n👋 Shutting down...")
+					i18n.Println("\n👋 Shutting down...")
+					flushDigest(cfg, alerts, true)
 					return
 				}
 			}
-			checkRules(cfg, stockClient, notifier, evaluator)
+			checkRules(cfg, stockClient, candles, alerts, evaluator, tracker, feed, ticks, minSeverity)
 		case <-sigChan:
-			fmt.Println("\n👋 Shutting down...")
+			i18n.Println("\n👋 Shutting down...")
+			flushDigest(cfg, alerts, true)
 			return
 		}
 	}
 }
 
-func checkRules(cfg *config.Config, stockClient *stock.Client, notifier *notify.Notifier, evaluator *rule.Evaluator) {
+// openTickStore opens the tick history store, returning nil if recording is disabled
+func openTickStore(cfg *config.Config) *store.TickStore {
+	if !cfg.History.IsEnabled() {
+		return nil
+	}
+	ticks, err := store.NewTickStore(cfg.History)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Warning: tick history disabled: %v\n", err)
+		return nil
+	}
+	return ticks
+}
+
+// openAlertLog opens the alert history, returning nil if alerts cannot be recorded
+func openAlertLog(cfg *config.Config) *store.AlertLog {
+	alertLog, err := store.NewAlertLog(cfg.History)
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Warning: alert history disabled: %v\n", err)
+		return nil
+	}
+	return alertLog
+}
+
+// openStateStore opens the persisted alert state, returning nil (in-memory state) on failure
+func openStateStore(cfg *config.Config) rule.StateStore {
+	state, err := store.NewStateStore(cfg.History, config.DefaultConfigPath())
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Warning: alert state will not persist: %v\n", err)
+		return nil
+	}
+	return state
+}
+
+// checkRules fetches every watched symbol and evaluates its rules, printing
+// alerts of at least minSeverity and notifying about new ones and about
+// quotes that keep failing or stay stale
+func checkRules(cfg *config.Config, stockClient *stock.Client, candles *stock.CandleCache, alerts *notify.Alerter, evaluator *rule.Evaluator, tracker *rule.Tracker, feed *rule.FeedMonitor, ticks *store.TickStore, minSeverity config.Severity) {
 	now := time.Now().Format("15:04:05")
-	fmt.Printf("\n[%s] Checking %d rules...\n", now, len(cfg.Rules))
+	i18n.Printf("\n[%s] Checking %d rules...\n", now, len(cfg.Rules))
+
+	// Quotes fetched this cycle, so pair legs that are also watched are fetched once
+	quotes := make(map[string]*stock.Quote)
+	getQuote := func(symbol, market string) (*stock.Quote, error) {
+		if q, ok := quotes[symbol]; ok {
+			return q, nil
+		}
+		q, err := stockClient.GetQuote(symbol, market)
+		if err != nil {
+			return nil, err
+		}
+		quotes[symbol] = q
+		return q, nil
+	}
+
+	for _, symbol := range cfg.Symbols() {
+		rules := cfg.RulesForSymbol(symbol)
+		name, market := cfg.SymbolInfo(symbol)
+
+		displayName := symbol
+		if name != "" {
+			displayName = fmt.Sprintf("%s(%s)", symbol, name)
+		}
 
-	for _, r := range cfg.Rules {
-		ruleCopy := r // Create a copy for the evaluator
+		// Skip symbols whose rules are all disabled
+		enabled := false
+		for _, r := range rules {
+			if r.IsEnabled() {
+				enabled = true
+			}
+		}
+		if !enabled {
+			continue
+		}
 
-		quote, err := stockClient.GetQuote(r.Symbol, r.Market)
+		// Fetch the quote once for every rule on the symbol
+		quote, err := getQuote(symbol, market)
+		feedAlerts := feed.Observe(symbol, market, quote, err, time.Now())
 		if err != nil {
-			fmt.Printf("  %s ❌ Error: %v\n", r.Symbol, err)
+			i18n.Printf("  %s ❌ Error: %v\n", symbol, err)
+			checkFeed(cfg, alerts, feed, symbol, nil, feedAlerts, minSeverity)
 			continue
 		}
 
-		result := evaluator.Evaluate(&ruleCopy, quote)
+		if ticks != nil {
+			if err := ticks.Append(quote); err != nil {
+				i18n.Printf("  %s ⚠️  Failed to record tick: %v\n", symbol, err)
+			}
+			if err := ticks.MaintainErr(); err != nil {
+				i18n.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
 
-		// Get current triggered conditions as a map
-		currentConditions := make(map[string]bool)
-		for _, reason := range result.Reasons {
-			currentConditions[reason] = true
+		in := rule.Input{
+			Quote:   quote,
+			Holding: cfg.GetHolding(symbol),
+			Market:  market,
 		}
 
-		// Initialize state for this symbol if not exists
-		if triggeredState[r.Symbol] == nil {
-			triggeredState[r.Symbol] = make(map[string]bool)
+		// Daily and intraday candles for indicator and crossover conditions
+		for res, bars := range rule.CandleNeeds(rules, in.Holding) {
+			history, err := candles.Get(symbol, market, res, bars)
+			if err != nil {
+				i18n.Printf("  %s ⚠️  Failed to fetch candles (%s): %v\n", symbol, res, err)
+				if lookback := rule.ChangeLookback(rules); res == "D" && lookback > 0 && ticks != nil {
+					// Changes can fall back to daily bars built from the recorded quotes
+					if local, err := ticks.Daily(symbol, market, lookback); err == nil {
+						in.LocalCandles = local
+						i18n.Printf("  %s 📼 Using daily bars from recorded quotes for changes\n", symbol)
+					}
+				}
+				continue
+			}
+			if res == "D" {
+				in.Candles = history
+			} else {
+				if in.Intraday == nil {
+					in.Intraday = make(map[string]*stock.Candle)
+				}
+				in.Intraday[res] = history
+			}
 		}
 
-		// Find newly triggered conditions (edge detection)
-		var newlyTriggered []string
-		for reason := range currentConditions {
-			if !triggeredState[r.Symbol][reason] {
-				newlyTriggered = append(newlyTriggered, reason)
+		// Other legs of pair rules, fetched in the same cycle
+		for _, r := range rules {
+			if !r.IsEnabled() || r.Pair == nil {
+				continue
+			}
+			for _, l := range r.Pair.Legs() {
+				if in.Legs == nil {
+					in.Legs = make(map[string]*rule.Leg)
+				}
+				if _, ok := in.Legs[l.Symbol]; ok {
+					continue
+				}
+				legQuote, err := getQuote(l.Symbol, l.Market)
+				if err != nil {
+					i18n.Printf("  %s ⚠️  Failed to fetch pair leg %s: %v\n", symbol, l.Symbol, err)
+					continue
+				}
+				leg := &rule.Leg{Quote: legQuote}
+				if lookback := rule.PairLookback(r); lookback > 0 {
+					if leg.Candles, err = candles.Daily(l.Symbol, l.Market, lookback); err != nil {
+						i18n.Printf("  %s ⚠️  Failed to fetch candles for %s: %v\n", symbol, l.Symbol, err)
+					}
+				}
+				in.Legs[l.Symbol] = leg
 			}
 		}
 
-		// Update state: set current conditions and clear conditions no longer met
-		triggeredState[r.Symbol] = currentConditions
+		// Evaluate every enabled rule, detecting conditions that just became true
+		outcomes, err := tracker.EvaluateAll(evaluator, rules, in, time.Now())
+		if err != nil {
+			i18n.Printf("  %s ⚠️  Failed to save alert state: %v\n", symbol, err)
+		}
+		anyTriggered, anyNew := false, false
+		for _, o := range outcomes {
+			anyTriggered = anyTriggered || o.Triggered()
+			anyNew = anyNew || len(o.New) > 0
+		}
 
 		// Format the status line
-		changeSign := ""
-		if quote.PercentChange >= 0 {
-			changeSign = "+"
-		}
 		status := "✓"
-		if result.Triggered() {
-			if len(newlyTriggered) > 0 {
+		if anyTriggered {
+			if anyNew {
 				status = "🔔" // New trigger
 			} else {
 				status = "⚠️" // Still triggered but already notified
 			}
 		}
 
-		displayName := r.Symbol
-		if r.Name != "" {
-			displayName = fmt.Sprintf("%s(%s)", r.Symbol, r.Name)
-		}
+		i18n.Printf("  %s $%.2f (%+.2f%%) %s\n", displayName, quote.CurrentPrice, quote.PercentChange, status)
+		checkFeed(cfg, alerts, feed, symbol, quote, feedAlerts, minSeverity)
 
-		fmt.Printf("  %s $%.2f (%s%.2f%%) %s\n",
-			displayName, quote.CurrentPrice, changeSign, quote.PercentChange, status)
-
-		// Print trigger reasons
-		if result.Triggered() {
-			for _, reason := range result.Reasons {
-				prefix := "→"
-				for _, nr := range newlyTriggered {
-					if nr == reason {
-						prefix = "🆕"
-						break
-					}
-				}
-				fmt.Printf("     %s %s\n", prefix, reason)
+		for _, o := range outcomes {
+			if o.TrailingStop > 0 {
+				i18n.Printf("     🛑 [%s] Trailing stop $%.2f (peak $%.2f)\n", o.Rule.ID, o.TrailingStop, o.TrailingPeak)
+			}
+			if o.Silence != nil {
+				printSilence(o.Rule.ID, o.Silence)
 			}
-		}
 
-		// Only send notification for newly triggered conditions
-		if len(newlyTriggered) > 0 && notifier.IsConfigured() {
-			title, body := result.FormatNotification()
-			if err := notifier.SendWithGroup(title, body, "stock-ping"); err != nil {
-				fmt.Printf("     ❌ Failed to send notification: %v\n", err)
-			} else {
-				fmt.Println("     📱 Bark notification sent")
+			// Print trigger reasons
+			for _, c := range o.Conditions {
+				printCondition(c, o.IsNew(c), slices.Contains(o.Queued, c), minSeverity)
 			}
+
+			// Only send notification for newly triggered conditions
+			printDelivery(alerts.Send(cfg, o.Rule.Symbol, o.Quote, o.New, o.FormatNotification))
 		}
 
 		// Small delay between API calls to avoid rate limiting
 		time.Sleep(200 * time.Millisecond)
 	}
+
+	if len(cfg.PortfolioRules) > 0 {
+		checkPortfolioRules(cfg, getQuote, alerts, evaluator, tracker, minSeverity)
+	}
+
+	sendDigest(cfg, alerts, tracker)
+	flushDigest(cfg, alerts, false)
+}
+
+// printDelivery prints what happened to alerts handed to the alerter
+func printDelivery(d notify.Delivery) {
+	if d.Batched {
+		i18n.Println("     🗂  Added to the digest")
+	}
+	if d.Pushed {
+		if d.SendErr != nil {
+			i18n.Printf("     ❌ Failed to send notification: %v\n", d.SendErr)
+		} else {
+			i18n.Println("     📱 Bark notification sent")
+		}
+	}
+	if d.RecordErr != nil {
+		i18n.Printf("     ⚠️  Failed to record alerts: %v\n", d.RecordErr)
+	}
+}
+
+// flushDigest sends the digest of collected alerts once it is due, or right away if force is set
+func flushDigest(cfg *config.Config, alerts *notify.Alerter, force bool) {
+	if d := alerts.Flush(cfg, force); d.Alerts > 0 {
+		i18n.Printf("  🗂  Sending digest of %d alerts\n", d.Alerts)
+		printDelivery(d)
+	}
+}
+
+// printCondition prints a met condition unless it is less severe than minSeverity
+func printCondition(c rule.Condition, isNew, queued bool, minSeverity config.Severity) {
+	if !c.Severity.AtLeast(minSeverity) {
+		return
+	}
+	prefix := "→"
+	if isNew {
+		prefix = "🆕"
+	}
+	if queued {
+		prefix = "🔕"
+	}
+	if icon := rule.SeverityIcon(c.Severity); icon != "" {
+		prefix += " " + icon
+	}
+	fmt.Printf("     %s [%s] %s\n", prefix, c.RuleID, c.Text)
+}
+
+// printSilence prints that a rule was snoozed or disabled from the dashboard
+func printSilence(id string, sil *rule.Silence) {
+	if sil.Disabled() {
+		i18n.Printf("     🔕 [%s] Disabled from the dashboard\n", id)
+	} else {
+		i18n.Printf("     💤 [%s] Snoozed until %s\n", id, sil.Until.Local().Format("01-02 15:04"))
+	}
+}
+
+// checkFeed prints how long symbol's quotes have been stale and notifies
+// about the feed-health conditions raised this cycle
+func checkFeed(cfg *config.Config, alerts *notify.Alerter, feed *rule.FeedMonitor, symbol string, quote *stock.Quote, conditions []rule.Condition, minSeverity config.Severity) {
+	if st := feed.Status(symbol); st != nil && st.Problem == rule.CondFeedStale {
+		i18n.Printf("     ⏳ Stale quote, %s old (%d cycles)\n", config.Duration(st.QuoteAge.Round(time.Minute)), st.Cycles)
+	}
+	for _, c := range conditions {
+		printCondition(c, true, false, minSeverity)
+		printDelivery(alerts.Send(cfg, symbol, quote, []rule.Condition{c}, func() (string, string) {
+			return rule.FormatFeedNotification(c)
+		}))
+	}
+}
+
+// sendDigest sends the alerts queued during quiet hours once they are over
+func sendDigest(cfg *config.Config, alerts *notify.Alerter, tracker *rule.Tracker) {
+	d := alerts.FlushQuietHours(cfg, tracker)
+	if d.StateErr != nil {
+		i18n.Printf("  🌙 ⚠️  Failed to save alert state: %v\n", d.StateErr)
+	}
+	if d.Alerts == 0 {
+		return
+	}
+	i18n.Printf("  🌙 Quiet hours over, sending %d queued alerts\n", d.Alerts)
+	printDelivery(d)
+}
+
+// checkPortfolioRules evaluates the portfolio rules against this cycle's quotes,
+// fetching holdings that are not otherwise watched
+func checkPortfolioRules(cfg *config.Config, getQuote func(symbol, market string) (*stock.Quote, error), alerts *notify.Alerter, evaluator *rule.Evaluator, tracker *rule.Tracker, minSeverity config.Severity) {
+	quotes := make(map[string]*stock.Quote)
+	for _, h := range cfg.Holdings {
+		_, market := cfg.SymbolInfo(h.Symbol)
+		quote, err := getQuote(h.Symbol, market)
+		if err != nil {
+			i18n.Printf("  💼 ⚠️  Failed to fetch %s: %v\n", h.Symbol, err)
+			continue
+		}
+		quotes[h.Symbol] = quote
+	}
+
+	portfolio := rule.NewPortfolio(cfg.Holdings, quotes)
+	outcomes, err := tracker.EvaluatePortfolio(evaluator, cfg.PortfolioRules, portfolio, time.Now())
+	if err != nil {
+		i18n.Printf("  💼 ⚠️  Failed to save alert state: %v\n", err)
+	}
+	if len(portfolio.Missing) > 0 {
+		i18n.Printf("  💼 Portfolio rules skipped, no quote for %s\n", strings.Join(portfolio.Missing, ", "))
+		return
+	}
+	if len(portfolio.Positions) == 0 {
+		return
+	}
+
+	i18n.Printf("  💼 Portfolio $%.2f P/L %+.2f%% today %+.2f%%\n",
+		portfolio.Value(), portfolio.PLPercent(), portfolio.DayChangePercent())
+	for _, o := range outcomes {
+		for _, c := range o.Conditions {
+			printCondition(c, o.IsNew(c), slices.Contains(o.Queued, c), minSeverity)
+		}
+
+		printDelivery(alerts.Send(cfg, o.Subject(), nil, o.New, o.FormatNotification))
+	}
 }
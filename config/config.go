@@ -1,13 +1,25 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
 
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/internal/filelock"
+	"github.com/congregalis/stock-ping/rule/expr"
 	"gopkg.in/yaml.v3"
 )
 
+// DateLayout is the format of dates in the config file
+const DateLayout = "2006-01-02"
+
 // Config represents the application configuration
 type Config struct {
 	Finnhub  FinnhubConfig `yaml:"finnhub"`
@@ -15,6 +27,14 @@ type Config struct {
 	Interval int           `yaml:"interval"` // Refresh interval in seconds
 	Rules    []Rule        `yaml:"rules"`
 	Holdings []Holding     `yaml:"holdings,omitempty"`
+	History  HistoryConfig `yaml:"history,omitempty"`
+
+	PortfolioRules []PortfolioRule  `yaml:"portfolio_rules,omitempty"` // Alerts on the holdings as a whole
+	QuietHours     *QuietHours      `yaml:"quiet_hours,omitempty"`     // Hold alerts back and send a digest afterwards
+	Language       string           `yaml:"language,omitempty"`        // Output language: en or zh-CN (default from LANG)
+	Digest         *DigestConfig    `yaml:"digest,omitempty"`          // Batch new alerts into summary notifications
+	Snooze         Duration         `yaml:"snooze,omitempty"`          // How long the dashboard snoozes a rule (default 1h)
+	FeedHealth     FeedHealthConfig `yaml:"feed_health,omitempty"`     // Alerts on quotes that keep failing or stay stale
 }
 
 // FinnhubConfig holds Finnhub API configuration
@@ -26,10 +46,94 @@ type FinnhubConfig struct {
 type BarkConfig struct {
 	ServerURL string `yaml:"server_url"` // e.g. https://api.day.app
 	Key       string `yaml:"key"`
+
+	Sounds map[Severity]string `yaml:"sounds,omitempty"` // Sound by severity, e.g. critical: alarm
+}
+
+// HistoryConfig controls the local tick history store
+type HistoryConfig struct {
+	Enabled          *bool  `yaml:"enabled,omitempty"`            // Record every fetched quote (default true)
+	Dir              string `yaml:"dir,omitempty"`                // Data directory (default ~/.local/share/stock-ping)
+	RetentionDays    int    `yaml:"retention_days,omitempty"`     // Delete ticks older than this (0 = keep forever)
+	CompactAfterDays int    `yaml:"compact_after_days,omitempty"` // Downsample ticks older than this (0 = never)
+	CompactInterval  int    `yaml:"compact_interval,omitempty"`   // Seconds between kept ticks after compaction (default 60)
+}
+
+// DigestConfig batches the new alerts of a refresh cycle, or of a longer
+// window, into one summary notification
+type DigestConfig struct {
+	Window    Duration `yaml:"window,omitempty"`    // Collect alerts this long before sending (default: one refresh cycle)
+	Immediate Severity `yaml:"immediate,omitempty"` // Alerts of at least this severity are sent right away (default critical)
+}
+
+// Batches reports whether alerts of the given severity go into the digest.
+// A nil digest batches nothing.
+func (d *DigestConfig) Batches(s Severity) bool {
+	if d == nil {
+		return false
+	}
+	immediate := d.Immediate
+	if immediate == "" {
+		immediate = SeverityCritical
+	}
+	return !s.AtLeast(immediate)
+}
+
+// FeedHealthConfig alerts when the quotes of a symbol keep failing or stay
+// stale during its session, and again when they recover
+type FeedHealthConfig struct {
+	Enabled    *bool    `yaml:"enabled,omitempty"`     // Send feed-health alerts (default true)
+	Cycles     int      `yaml:"cycles,omitempty"`      // Consecutive bad refresh cycles before alerting (default 3)
+	StaleAfter Duration `yaml:"stale_after,omitempty"` // Quote age in an open session that counts as stale (default 30m)
+	Severity   Severity `yaml:"severity,omitempty"`    // Severity of the alerts (default warning)
+}
+
+// IsEnabled returns true unless feed-health alerts were explicitly disabled
+func (f FeedHealthConfig) IsEnabled() bool {
+	return f.Enabled == nil || *f.Enabled
+}
+
+// GetCycles returns how many bad cycles in a row raise an alert
+func (f FeedHealthConfig) GetCycles() int {
+	if f.Cycles <= 0 {
+		return 3
+	}
+	return f.Cycles
 }
 
-// Rule defines a stock monitoring rule
+// GetStaleAfter returns the quote age that counts as stale
+func (f FeedHealthConfig) GetStaleAfter() time.Duration {
+	if f.StaleAfter <= 0 {
+		return 30 * time.Minute
+	}
+	return time.Duration(f.StaleAfter)
+}
+
+// GetSeverity returns the severity of feed-health alerts
+func (f FeedHealthConfig) GetSeverity() Severity {
+	if f.Severity == "" {
+		return SeverityWarning
+	}
+	return f.Severity
+}
+
+// IsEnabled returns true unless history recording was explicitly disabled
+func (h HistoryConfig) IsEnabled() bool {
+	return h.Enabled == nil || *h.Enabled
+}
+
+// DataDir returns the configured data directory or the default one
+func (h HistoryConfig) DataDir() string {
+	if h.Dir != "" {
+		return h.Dir
+	}
+	return DefaultDataDir()
+}
+
+// Rule defines a stock monitoring rule. A symbol may have several rules.
 type Rule struct {
+	ID          string   `yaml:"id,omitempty"`      // Stable rule ID, assigned automatically if empty
+	Enabled     *bool    `yaml:"enabled,omitempty"` // Evaluate this rule (default true)
 	Symbol      string   `yaml:"symbol"`
 	Market      string   `yaml:"market,omitempty"`       // "US", "CN", "HK", "CRYPTO", "FOREX"
 	Name        string   `yaml:"name,omitempty"`         // Optional display name
@@ -37,6 +141,334 @@ type Rule struct {
 	PriceBelow  *float64 `yaml:"price_below,omitempty"`  // Trigger if price < threshold
 	ChangeAbove *float64 `yaml:"change_above,omitempty"` // Trigger if change% > threshold
 	ChangeBelow *float64 `yaml:"change_below,omitempty"` // Trigger if change% < threshold
+	LimitHit    bool     `yaml:"limit_hit,omitempty"`    // Trigger on limit-up/limit-down (A-shares)
+	LimitNear   *float64 `yaml:"limit_near,omitempty"`   // Trigger if within X% of a limit band (A-shares)
+	When        string   `yaml:"when,omitempty"`         // Expression, e.g. "price > sma(50) && rsi(14) < 30"
+
+	// Position conditions, measured against the symbol's holding
+	GainAbove *float64 `yaml:"gain_above,omitempty"` // Trigger if gain% vs cost basis > threshold (take-profit)
+	LossBelow *float64 `yaml:"loss_below,omitempty"` // Trigger if gain% vs cost basis < threshold (stop-loss, negative)
+	PLAbove   *float64 `yaml:"pl_above,omitempty"`   // Trigger if position P/L > amount
+	PLBelow   *float64 `yaml:"pl_below,omitempty"`   // Trigger if position P/L < amount (negative for a loss)
+
+	// Trailing stops, measured from the highest price since the holding was opened or the rule created
+	TrailingStopPct    *float64 `yaml:"trailing_stop_pct,omitempty"`    // Trigger if price falls X% from the peak
+	TrailingStopAmount *float64 `yaml:"trailing_stop_amount,omitempty"` // Trigger if price falls X from the peak
+
+	Crossovers *Crossovers `yaml:"crossovers,omitempty"` // Indicator crossovers between consecutive bars
+
+	Pair *Pair `yaml:"pair,omitempty"` // Spread between this symbol and a second one
+
+	// Gaps, new highs/lows and range breakouts
+	GapAbove        *float64  `yaml:"gap_above,omitempty"`          // Trigger if the open gaps up > X% vs previous close
+	GapBelow        *float64  `yaml:"gap_below,omitempty"`          // Trigger if the open gaps down < X% (negative value)
+	NewDayHighAfter *Duration `yaml:"new_day_high_after,omitempty"` // Trigger on a new intraday high once the session is this old
+	NewDayLowAfter  *Duration `yaml:"new_day_low_after,omitempty"`  // Trigger on a new intraday low once the session is this old
+	BreakPrevHigh   bool      `yaml:"break_prev_high,omitempty"`    // Trigger if price breaks above the previous day's high
+	BreakPrevLow    bool      `yaml:"break_prev_low,omitempty"`     // Trigger if price breaks below the previous day's low
+	NewHighDays     int       `yaml:"new_high_days,omitempty"`      // Trigger if price exceeds the N-day high
+	NewLowDays      int       `yaml:"new_low_days,omitempty"`       // Trigger if price falls below the N-day low
+
+	Changes []PeriodChange `yaml:"changes,omitempty"` // Change over several days, since a date or from an N-day high/low
+
+	Created string `yaml:"created,omitempty"` // Date the rule was added (YYYY-MM-DD)
+
+	Active *Schedule `yaml:"active,omitempty"` // Only evaluate the rule within this schedule
+
+	// Alert policy, applied to each condition of the rule
+	AlertPolicy `yaml:",inline"`
+
+	// Expr is the compiled When expression, set by Compile
+	Expr *expr.Expr `yaml:"-"`
+}
+
+// AlertPolicy controls how often and how urgently the conditions of a rule alert
+type AlertPolicy struct {
+	Cooldown    Duration `yaml:"cooldown,omitempty"`     // Minimum time between alerts for a condition
+	RearmBand   *Band    `yaml:"rearm_band,omitempty"`   // Distance back past the threshold before a condition re-arms
+	RepeatEvery Duration `yaml:"repeat_every,omitempty"` // Remind while a condition stays met
+
+	Severity          Severity            `yaml:"severity,omitempty"`           // info, warning or critical (default info)
+	ConditionSeverity map[string]Severity `yaml:"condition_severity,omitempty"` // Overrides by condition type, e.g. loss_below: critical
+}
+
+// SeverityOf returns the severity of a condition of the given type
+func (p AlertPolicy) SeverityOf(condType string) Severity {
+	if s, ok := p.ConditionSeverity[condType]; ok {
+		return s
+	}
+	if p.Severity != "" {
+		return p.Severity
+	}
+	return SeverityInfo
+}
+
+// Condition types accepted as condition_severity keys. They mirror the Cond*
+// constants of package rule, which cannot be imported here.
+var (
+	ruleConditionTypes = []string{
+		"price_above", "price_below", "change_above", "change_below",
+		"limit_up", "limit_down", "near_limit_up", "near_limit_down",
+		"gain_above", "loss_below", "pl_above", "pl_below",
+		"trailing_stop_pct", "trailing_stop_amount",
+		"gap_up", "gap_down", "new_day_high", "new_day_low",
+		"break_prev_high", "break_prev_low", "new_high_days", "new_low_days",
+		"period_change_above", "period_change_below",
+		"cross", "pair_above", "pair_below", "pair_zscore", "when",
+	}
+	portfolioConditionTypes = []string{
+		"portfolio_value_above", "portfolio_value_below",
+		"portfolio_pl_above", "portfolio_pl_below",
+		"portfolio_pl_pct_above", "portfolio_pl_pct_below",
+		"portfolio_day_change_above", "portfolio_day_change_below",
+	}
+)
+
+// validateConditionSeverity checks that condition_severity only names the given condition types
+func (p AlertPolicy) validateConditionSeverity(types []string) error {
+	for _, typ := range slices.Sorted(maps.Keys(p.ConditionSeverity)) {
+		if !slices.Contains(types, typ) {
+			return fmt.Errorf("unknown condition type %q in condition_severity", typ)
+		}
+	}
+	return nil
+}
+
+// PortfolioRule defines an alert on the holdings as a whole
+type PortfolioRule struct {
+	ID      string `yaml:"id,omitempty"`      // Stable identifier, e.g. portfolio-1
+	Name    string `yaml:"name,omitempty"`    // Display name, e.g. 回撤保护
+	Enabled *bool  `yaml:"enabled,omitempty"` // Defaults to true
+
+	ValueAbove     *float64 `yaml:"value_above,omitempty"`      // Trigger if total market value > amount
+	ValueBelow     *float64 `yaml:"value_below,omitempty"`      // Trigger if total market value < amount
+	PLAbove        *float64 `yaml:"pl_above,omitempty"`         // Trigger if total P/L > amount
+	PLBelow        *float64 `yaml:"pl_below,omitempty"`         // Trigger if total P/L < amount (negative for a loss)
+	PLPctAbove     *float64 `yaml:"pl_pct_above,omitempty"`     // Trigger if total P/L vs cost > X%
+	PLPctBelow     *float64 `yaml:"pl_pct_below,omitempty"`     // Trigger if total P/L vs cost < X% (negative value)
+	DayChangeAbove *float64 `yaml:"day_change_above,omitempty"` // Trigger if today's change in value > X%
+	DayChangeBelow *float64 `yaml:"day_change_below,omitempty"` // Trigger if today's change in value < X% (negative value)
+
+	AlertPolicy `yaml:",inline"`
+}
+
+// IsEnabled returns true unless the rule was explicitly disabled
+func (r *PortfolioRule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// IsEnabled returns true unless the rule was explicitly disabled
+func (r *Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// HasTrailingStop returns true if the rule has a trailing stop condition
+func (r *Rule) HasTrailingStop() bool {
+	return r.TrailingStopPct != nil || r.TrailingStopAmount != nil
+}
+
+// HasConditions returns true if the rule has at least one condition to alert on
+func (r *Rule) HasConditions() bool {
+	return r.PriceAbove != nil || r.PriceBelow != nil || r.ChangeAbove != nil || r.ChangeBelow != nil ||
+		r.LimitHit || r.LimitNear != nil || strings.TrimSpace(r.When) != "" ||
+		r.GainAbove != nil || r.LossBelow != nil || r.PLAbove != nil || r.PLBelow != nil ||
+		r.HasTrailingStop() || r.Crossovers != nil || r.Pair != nil ||
+		r.GapAbove != nil || r.GapBelow != nil || r.NewDayHighAfter != nil || r.NewDayLowAfter != nil ||
+		r.BreakPrevHigh || r.BreakPrevLow || r.NewHighDays > 0 || r.NewLowDays > 0 ||
+		len(r.Changes) > 0
+}
+
+// Compile parses and type-checks the rule's when expression
+func (r *Rule) Compile() error {
+	r.Expr = nil
+	if strings.TrimSpace(r.When) == "" {
+		return nil
+	}
+	e, err := expr.Compile(r.When)
+	if err != nil {
+		return err
+	}
+	r.Expr = e
+	return nil
+}
+
+// Crossovers configures indicator crossover conditions. Each fires on the bar
+// where the sign of the difference between the two lines changes.
+type Crossovers struct {
+	Resolution string  `yaml:"resolution,omitempty"`  // Candle resolution: D (default), 60, 30, 15, 5 or 1 minutes
+	PriceSMA   []int   `yaml:"price_sma,omitempty"`   // Price crossing SMA(N), e.g. [50, 200]
+	SMACross   []int   `yaml:"sma_cross,omitempty"`   // [fast, slow]: golden cross (up) and death cross (down)
+	MACDSignal bool    `yaml:"macd_signal,omitempty"` // MACD(12, 26, 9) crossing its signal line
+	RSI        int     `yaml:"rsi,omitempty"`         // RSI period for overbought/oversold crossings
+	Overbought float64 `yaml:"overbought,omitempty"`  // RSI overbought level (default 70)
+	Oversold   float64 `yaml:"oversold,omitempty"`    // RSI oversold level (default 30)
+}
+
+// CrossoverResolutions lists the supported crossover candle resolutions
+var CrossoverResolutions = []string{"D", "60", "30", "15", "5", "1"}
+
+// GetResolution returns the candle resolution, defaulting to daily
+func (x *Crossovers) GetResolution() string {
+	if x.Resolution == "" {
+		return "D"
+	}
+	return x.Resolution
+}
+
+// RSILevels returns the overbought and oversold levels with defaults applied
+func (x *Crossovers) RSILevels() (overbought, oversold float64) {
+	overbought, oversold = x.Overbought, x.Oversold
+	if overbought == 0 {
+		overbought = 70
+	}
+	if oversold == 0 {
+		oversold = 30
+	}
+	return overbought, oversold
+}
+
+// Validate checks the crossover periods and resolution
+func (x *Crossovers) Validate() error {
+	if !slices.Contains(CrossoverResolutions, x.GetResolution()) {
+		return fmt.Errorf("unsupported resolution %q (use one of %s)", x.Resolution, strings.Join(CrossoverResolutions, ", "))
+	}
+	for _, n := range x.PriceSMA {
+		if n < 1 {
+			return fmt.Errorf("price_sma periods must be positive")
+		}
+	}
+	if len(x.SMACross) > 0 && (len(x.SMACross) != 2 || x.SMACross[0] < 1 || x.SMACross[0] >= x.SMACross[1]) {
+		return fmt.Errorf("sma_cross must be [fast, slow] with 0 < fast < slow")
+	}
+	if x.RSI < 0 {
+		return fmt.Errorf("rsi period must be positive")
+	}
+	if overbought, oversold := x.RSILevels(); oversold >= overbought {
+		return fmt.Errorf("rsi oversold level must be below overbought")
+	}
+	return nil
+}
+
+// Pair formulas, comparing leg A (the rule's symbol) with leg B
+const (
+	PairRatio     = "ratio"      // A / B
+	PairDiff      = "diff"       // A - B
+	PairSpreadPct = "spread_pct" // (A - B) / B * 100
+)
+
+// Pair configures a spread between the rule's symbol (leg A) and a second
+// symbol (leg B). Leg B is scaled by Multiplier and converted with the FX
+// symbol's price into leg A's currency before the formula is applied.
+type Pair struct {
+	Symbol     string   `yaml:"symbol"`               // Leg B symbol
+	Market     string   `yaml:"market,omitempty"`     // Leg B market
+	Formula    string   `yaml:"formula,omitempty"`    // ratio (default), diff or spread_pct
+	Multiplier float64  `yaml:"multiplier,omitempty"` // Leg B units per leg A unit, e.g. 8 shares per ADR (default 1)
+	FX         string   `yaml:"fx,omitempty"`         // Price of leg B's currency in leg A's, e.g. HKDUSD=X
+	FXMarket   string   `yaml:"fx_market,omitempty"`  // Market of the FX symbol (default FOREX)
+	Above      *float64 `yaml:"above,omitempty"`      // Trigger if the spread > value
+	Below      *float64 `yaml:"below,omitempty"`      // Trigger if the spread < value
+	ZScore     float64  `yaml:"zscore,omitempty"`     // Trigger if the spread is this many standard deviations from its mean
+	Window     int      `yaml:"window,omitempty"`     // Days in the rolling mean (default 20)
+}
+
+// Leg is a symbol and its market
+type Leg struct {
+	Symbol string
+	Market string
+}
+
+// GetFormula returns the formula, defaulting to a ratio
+func (p *Pair) GetFormula() string {
+	if p.Formula == "" {
+		return PairRatio
+	}
+	return p.Formula
+}
+
+// GetMultiplier returns the leg B multiplier, defaulting to 1
+func (p *Pair) GetMultiplier() float64 {
+	if p.Multiplier == 0 {
+		return 1
+	}
+	return p.Multiplier
+}
+
+// GetWindow returns the rolling window in days, defaulting to 20
+func (p *Pair) GetWindow() int {
+	if p.Window == 0 {
+		return 20
+	}
+	return p.Window
+}
+
+// Legs returns the other symbols the pair needs quotes for: leg B and the FX rate
+func (p *Pair) Legs() []Leg {
+	legs := []Leg{{Symbol: p.Symbol, Market: p.Market}}
+	if p.FX != "" {
+		market := p.FXMarket
+		if market == "" {
+			market = "FOREX"
+		}
+		legs = append(legs, Leg{Symbol: p.FX, Market: market})
+	}
+	return legs
+}
+
+// Validate checks the pair's symbol, formula and z-score settings
+func (p *Pair) Validate() error {
+	if p.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	switch p.GetFormula() {
+	case PairRatio, PairDiff, PairSpreadPct:
+	default:
+		return fmt.Errorf("unknown formula %q (use ratio, diff or spread_pct)", p.Formula)
+	}
+	if p.Multiplier < 0 {
+		return fmt.Errorf("multiplier must be positive")
+	}
+	if p.ZScore < 0 || p.Window < 0 {
+		return fmt.Errorf("zscore and window must be positive")
+	}
+	if p.ZScore > 0 && p.GetWindow() < 2 {
+		return fmt.Errorf("window must be at least 2 days")
+	}
+	return nil
+}
+
+// PeriodChange compares the price with a reference price from daily history,
+// set by exactly one of Days, Since, FromHigh and FromLow
+type PeriodChange struct {
+	Days     int      `yaml:"days,omitempty"`      // Close N trading days ago
+	Since    string   `yaml:"since,omitempty"`     // Last close before this date (YYYY-MM-DD)
+	FromHigh int      `yaml:"from_high,omitempty"` // Highest high of the last N trading days, e.g. 252 for the 52-week high
+	FromLow  int      `yaml:"from_low,omitempty"`  // Lowest low of the last N trading days
+	Above    *float64 `yaml:"above,omitempty"`     // Trigger if change% vs the reference > value
+	Below    *float64 `yaml:"below,omitempty"`     // Trigger if change% vs the reference < value (negative)
+}
+
+// Validate checks that the change has a single reference and a threshold
+func (p *PeriodChange) Validate() error {
+	refs := 0
+	for _, set := range []bool{p.Days != 0, p.Since != "", p.FromHigh != 0, p.FromLow != 0} {
+		if set {
+			refs++
+		}
+	}
+	if refs != 1 {
+		return fmt.Errorf("set exactly one of days, since, from_high and from_low")
+	}
+	if p.Days < 0 || p.FromHigh < 0 || p.FromLow < 0 {
+		return fmt.Errorf("days, from_high and from_low must be positive")
+	}
+	if _, err := ParseDate(p.Since); err != nil {
+		return fmt.Errorf("invalid since date: %w", err)
+	}
+	if p.Above == nil && p.Below == nil {
+		return fmt.Errorf("above or below is required")
+	}
+	return nil
 }
 
 // Holding defines a user's stock position
@@ -44,6 +476,7 @@ type Holding struct {
 	Symbol    string  `yaml:"symbol"`
 	Quantity  float64 `yaml:"quantity"`
 	CostPrice float64 `yaml:"cost_price"`
+	Opened    string  `yaml:"opened,omitempty"` // Date the position was opened (YYYY-MM-DD)
 }
 
 // DefaultConfigPath returns the default config file path
@@ -55,6 +488,19 @@ func DefaultConfigPath() string {
 	return filepath.Join(home, ".stock-ping.yaml")
 }
 
+// DefaultDataDir returns the default directory for local data such as tick history.
+// It honours XDG_DATA_HOME and falls back to ~/.local/share/stock-ping.
+func DefaultDataDir() string {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "stock-ping")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".stock-ping-data"
+	}
+	return filepath.Join(home, ".local", "share", "stock-ping")
+}
+
 // Load loads configuration from the default path
 func Load() (*Config, error) {
 	return LoadFrom(DefaultConfigPath())
@@ -87,71 +533,451 @@ func LoadFrom(path string) (*Config, error) {
 		cfg.Bark.ServerURL = "https://api.day.app"
 	}
 
+	if err := cfg.assignRuleIDs(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := cfg.compileRules(path, data); err != nil {
+		return nil, err
+	}
+
+	// Everything printed after loading follows the configured language
+	i18n.Use(cfg.Language)
+
 	return &cfg, nil
 }
 
+// RuleIDs returns the IDs of the rules and portfolio rules in the config file
+// at path, as Load would assign them
+func RuleIDs(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if err := cfg.assignRuleIDs(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	ids := make(map[string]bool)
+	for _, r := range cfg.Rules {
+		ids[r.ID] = true
+	}
+	for _, r := range cfg.PortfolioRules {
+		ids[r.ID] = true
+	}
+	return ids, nil
+}
+
+// compileRules compiles every rule expression, reporting errors with their
+// position in the config file
+func (c *Config) compileRules(path string, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	whenNodes := ruleFieldNodes(&doc, "when")
+
+	for i := range c.Rules {
+		err := c.Rules[i].Compile()
+		if err == nil {
+			continue
+		}
+
+		var exprErr *expr.Error
+		var n *yaml.Node
+		if i < len(whenNodes) {
+			n = whenNodes[i]
+		}
+		if n == nil || !errors.As(err, &exprErr) {
+			return fmt.Errorf("%s: rule %s: invalid when expression: %w", path, c.Rules[i].ID, err)
+		}
+
+		// Translate the offset within the expression to a file position.
+		// Literal block scalars keep their newlines; they start on the line after the indicator.
+		if n.Style == yaml.LiteralStyle {
+			line := n.Line + 1 + strings.Count(c.Rules[i].When[:exprErr.Pos], "\n")
+			return fmt.Errorf("%s:%d: rule %s: invalid when expression: %s",
+				path, line, c.Rules[i].ID, exprErr.Msg)
+		}
+		if n.Style == yaml.FoldedStyle {
+			line, col := foldedPosition(data, n, c.Rules[i].When, exprErr.Pos)
+			return fmt.Errorf("%s:%d:%d: rule %s: invalid when expression: %s",
+				path, line, col, c.Rules[i].ID, exprErr.Msg)
+		}
+		col := n.Column + exprErr.Pos
+		if n.Style == yaml.DoubleQuotedStyle || n.Style == yaml.SingleQuotedStyle {
+			col++ // Opening quote
+		}
+		return fmt.Errorf("%s:%d:%d: rule %s: invalid when expression: %s",
+			path, n.Line, col, c.Rules[i].ID, exprErr.Msg)
+	}
+	return nil
+}
+
+// foldedPosition maps offset pos in value, the content of the folded block
+// scalar n, to a line and column of the file. Folding only turns line breaks
+// into spaces, so the non-space characters before pos locate it in the raw text.
+func foldedPosition(data []byte, n *yaml.Node, value string, pos int) (line, col int) {
+	skip := 0
+	for _, r := range value[:pos] {
+		if !unicode.IsSpace(r) {
+			skip++
+		}
+	}
+
+	// The content starts on the line after the indicator; n.Line is 1-based
+	lines := strings.Split(string(data), "\n")
+	line, col = n.Line+1, 1
+	for i := n.Line; i < len(lines); i++ {
+		for j, r := range lines[i] {
+			if unicode.IsSpace(r) {
+				continue
+			}
+			if skip == 0 {
+				return i + 1, j + 1
+			}
+			skip--
+			line, col = i+1, j+2 // Just past the character, for errors at the end
+		}
+	}
+	return line, col
+}
+
+// sectionItems returns the entries of the top-level sequence key
+func sectionItems(doc *yaml.Node, key string) []*yaml.Node {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	if seq := mappingValue(doc.Content[0], key); seq != nil && seq.Kind == yaml.SequenceNode {
+		return seq.Content
+	}
+	return nil
+}
+
+// mappingValue returns the value node of key in a mapping, nil if absent
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for j := 0; j+1 < len(m.Content); j += 2 {
+		if m.Content[j].Value == key {
+			return m.Content[j+1]
+		}
+	}
+	return nil
+}
+
+// ruleFieldNodes returns the value node of field for each entry in rules,
+// or nil where the field is absent
+func ruleFieldNodes(doc *yaml.Node, field string) []*yaml.Node {
+	var nodes []*yaml.Node
+	for _, item := range sectionItems(doc, "rules") {
+		nodes = append(nodes, mappingValue(item, field))
+	}
+	return nodes
+}
+
 // Save saves configuration to the default path
 func (c *Config) Save() error {
 	return c.SaveTo(DefaultConfigPath())
 }
 
-// SaveTo saves configuration to a specific path
+// SaveTo saves configuration to a specific path. The file is written under
+// its lock to a temporary file and renamed into place, so readers never see
+// a partial config.
 func (c *Config) SaveTo(path string) error {
 	data, err := yaml.Marshal(c)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	// Replace the file a symlinked config points to, not the link
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	lock, err := filelock.Acquire(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	defer lock.Release()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
 	return nil
 }
 
-// AddRule adds a new rule to the configuration
-func (c *Config) AddRule(rule Rule) error {
-	// Check if symbol already exists
-	for i, r := range c.Rules {
-		if r.Symbol == rule.Symbol {
-			// Update existing rule
-			c.Rules[i] = rule
-			return nil
+// assignRuleIDs gives every rule without an ID one in memory and rejects
+// duplicates. The IDs are written to the file by the next command that saves
+// the config.
+func (c *Config) assignRuleIDs() error {
+	seen := make(map[string]bool)
+	for _, r := range c.Rules {
+		if r.ID == "" {
+			continue
+		}
+		if seen[r.ID] {
+			return fmt.Errorf("duplicate rule id %q", r.ID)
+		}
+		seen[r.ID] = true
+	}
+	for _, r := range c.PortfolioRules {
+		if r.ID == "" {
+			continue
+		}
+		if seen[r.ID] {
+			return fmt.Errorf("duplicate rule id %q", r.ID)
+		}
+		seen[r.ID] = true
+	}
+
+	for i := range c.Rules {
+		if c.Rules[i].ID == "" {
+			c.Rules[i].ID = c.nextRuleID(c.Rules[i].Symbol)
+		}
+	}
+	for i := range c.PortfolioRules {
+		if c.PortfolioRules[i].ID == "" {
+			c.PortfolioRules[i].ID = c.nextRuleID("portfolio")
 		}
 	}
-	c.Rules = append(c.Rules, rule)
 	return nil
 }
 
-// RemoveRule removes a rule by symbol
-func (c *Config) RemoveRule(symbol string) bool {
+// validate checks rule and holding fields that YAML decoding cannot
+func (c *Config) validate() error {
+	if c.Language != "" {
+		if _, ok := i18n.Parse(c.Language); !ok {
+			return fmt.Errorf("unsupported language %q (use en or zh-CN)", c.Language)
+		}
+	}
+	for _, r := range c.Rules {
+		if _, err := ParseDate(r.Created); err != nil {
+			return fmt.Errorf("rule %s: invalid created date: %w", r.ID, err)
+		}
+		if r.Crossovers != nil {
+			if err := r.Crossovers.Validate(); err != nil {
+				return fmt.Errorf("rule %s: invalid crossovers: %w", r.ID, err)
+			}
+		}
+		if r.Active != nil {
+			if err := r.Active.Validate(); err != nil {
+				return fmt.Errorf("rule %s: invalid active schedule: %w", r.ID, err)
+			}
+		}
+		if r.Pair != nil {
+			if err := r.Pair.Validate(); err != nil {
+				return fmt.Errorf("rule %s: invalid pair: %w", r.ID, err)
+			}
+		}
+		if r.NewHighDays < 0 || r.NewLowDays < 0 {
+			return fmt.Errorf("rule %s: new_high_days and new_low_days must be positive", r.ID)
+		}
+		for _, pc := range r.Changes {
+			if err := pc.Validate(); err != nil {
+				return fmt.Errorf("rule %s: invalid change: %w", r.ID, err)
+			}
+		}
+		if err := r.validateConditionSeverity(ruleConditionTypes); err != nil {
+			return fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+	}
+	for _, r := range c.PortfolioRules {
+		if err := r.validateConditionSeverity(portfolioConditionTypes); err != nil {
+			return fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+	}
+	if c.FeedHealth.Cycles < 0 || c.FeedHealth.StaleAfter < 0 {
+		return fmt.Errorf("feed_health: cycles and stale_after must be positive")
+	}
+	if c.QuietHours != nil {
+		if err := c.QuietHours.Validate(); err != nil {
+			return fmt.Errorf("invalid quiet_hours: %w", err)
+		}
+	}
+	for _, h := range c.Holdings {
+		if _, err := ParseDate(h.Opened); err != nil {
+			return fmt.Errorf("holding %s: invalid opened date: %w", h.Symbol, err)
+		}
+	}
+	return nil
+}
+
+// ParseDate parses a YYYY-MM-DD date in local time, returning the zero time for ""
+func ParseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation(DateLayout, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD, got %q", s)
+	}
+	return t, nil
+}
+
+// nextRuleID returns the first unused ID of the form <symbol>-<n>, e.g. aapl-1
+func (c *Config) nextRuleID(symbol string) string {
+	base := strings.ToLower(symbol)
+	for n := 1; ; n++ {
+		id := fmt.Sprintf("%s-%d", base, n)
+		if c.GetRule(id) == nil && c.GetPortfolioRule(id) == nil {
+			return id
+		}
+	}
+}
+
+// AddRule adds a rule and returns its ID. If the rule has the ID of an
+// existing rule, that rule is replaced instead.
+func (c *Config) AddRule(rule Rule) (string, error) {
+	if rule.Symbol == "" {
+		return "", fmt.Errorf("rule symbol is required")
+	}
+
+	if rule.ID != "" {
+		for i, r := range c.Rules {
+			if r.ID == rule.ID {
+				// Update existing rule
+				if rule.Created == "" {
+					rule.Created = r.Created
+				}
+				c.Rules[i] = rule
+				return rule.ID, nil
+			}
+		}
+	} else {
+		rule.ID = c.nextRuleID(rule.Symbol)
+	}
+
+	if rule.Created == "" {
+		rule.Created = time.Now().Format(DateLayout)
+	}
+	c.Rules = append(c.Rules, rule)
+	return rule.ID, nil
+}
+
+// RemoveRule removes a symbol or portfolio rule by ID
+func (c *Config) RemoveRule(id string) bool {
 	for i, r := range c.Rules {
-		if r.Symbol == symbol {
+		if r.ID == id {
 			c.Rules = append(c.Rules[:i], c.Rules[i+1:]...)
 			return true
 		}
 	}
+	for i, r := range c.PortfolioRules {
+		if r.ID == id {
+			c.PortfolioRules = append(c.PortfolioRules[:i], c.PortfolioRules[i+1:]...)
+			return true
+		}
+	}
 	return false
 }
 
-// GetRule returns a rule by symbol
-func (c *Config) GetRule(symbol string) *Rule {
+// AddPortfolioRule adds a portfolio rule and returns its ID. If the rule has
+// the ID of an existing portfolio rule, that rule is replaced instead.
+func (c *Config) AddPortfolioRule(rule PortfolioRule) string {
+	if rule.ID != "" {
+		for i, r := range c.PortfolioRules {
+			if r.ID == rule.ID {
+				c.PortfolioRules[i] = rule
+				return rule.ID
+			}
+		}
+	} else {
+		rule.ID = c.nextRuleID("portfolio")
+	}
+	c.PortfolioRules = append(c.PortfolioRules, rule)
+	return rule.ID
+}
+
+// GetPortfolioRule returns a portfolio rule by ID
+func (c *Config) GetPortfolioRule(id string) *PortfolioRule {
+	for i := range c.PortfolioRules {
+		if c.PortfolioRules[i].ID == id {
+			return &c.PortfolioRules[i]
+		}
+	}
+	return nil
+}
+
+// GetRule returns a rule by ID
+func (c *Config) GetRule(id string) *Rule {
 	for i := range c.Rules {
-		if c.Rules[i].Symbol == symbol {
+		if c.Rules[i].ID == id {
 			return &c.Rules[i]
 		}
 	}
 	return nil
 }
 
-// AddHolding adds or updates a holding in the configuration
+// RulesForSymbol returns every rule attached to symbol, enabled or not
+func (c *Config) RulesForSymbol(symbol string) []*Rule {
+	var rules []*Rule
+	for i := range c.Rules {
+		if c.Rules[i].Symbol == symbol {
+			rules = append(rules, &c.Rules[i])
+		}
+	}
+	return rules
+}
+
+// Symbols returns the unique symbols with rules, in config order
+func (c *Config) Symbols() []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, r := range c.Rules {
+		if !seen[r.Symbol] {
+			seen[r.Symbol] = true
+			symbols = append(symbols, r.Symbol)
+		}
+	}
+	return symbols
+}
+
+// SnoozeDuration returns how long the dashboard snoozes a rule
+func (c *Config) SnoozeDuration() time.Duration {
+	if c.Snooze <= 0 {
+		return time.Hour
+	}
+	return time.Duration(c.Snooze)
+}
+
+// SymbolInfo returns the display name and market for symbol from its rules
+func (c *Config) SymbolInfo(symbol string) (name, market string) {
+	for _, r := range c.RulesForSymbol(symbol) {
+		if name == "" {
+			name = r.Name
+		}
+		if market == "" {
+			market = r.Market
+		}
+	}
+	return name, market
+}
+
+// AddHolding adds or updates a holding in the configuration. A new holding
+// is dated today unless it has an opened date; an updated one keeps its date.
 func (c *Config) AddHolding(holding Holding) {
 	for i, h := range c.Holdings {
 		if h.Symbol == holding.Symbol {
+			if holding.Opened == "" {
+				holding.Opened = h.Opened
+			}
 			c.Holdings[i] = holding
 			return
 		}
 	}
+	if holding.Opened == "" {
+		holding.Opened = time.Now().Format(DateLayout)
+	}
 	c.Holdings = append(c.Holdings, holding)
 }
 
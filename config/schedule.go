@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// weekdays maps day names accepted in schedules to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseClock parses "HH:MM" into minutes since midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseTimeRange parses "HH:MM-HH:MM" into minutes since midnight. The end
+// may be before the start for ranges that wrap past midnight.
+func parseTimeRange(s string) (start, end int, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid time range %q (expected HH:MM-HH:MM)", s)
+	}
+	if start, err = parseClock(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(to); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// inRange reports whether minute falls in [start, end), wrapping past midnight
+func inRange(minute, start, end int) bool {
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// Schedule restricts when a rule is evaluated. Times are in the market's timezone.
+type Schedule struct {
+	Days  []string `yaml:"days,omitempty"`  // Days of week, e.g. [mon, tue, wed, thu, fri]
+	Times []string `yaml:"times,omitempty"` // Time ranges, e.g. ["09:30-10:00", "15:00-16:00"]
+	First Duration `yaml:"first,omitempty"` // Only within this long after the session opens
+}
+
+// Validate checks the day names and time ranges
+func (s *Schedule) Validate() error {
+	for _, d := range s.Days {
+		if _, ok := weekdays[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("invalid day %q (use mon, tue, ... sun)", d)
+		}
+	}
+	for _, r := range s.Times {
+		if _, _, err := parseTimeRange(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Contains reports whether t, in the market's timezone, is within the
+// schedule. sessionOpen is when the market's current session opened.
+func (s *Schedule) Contains(t, sessionOpen time.Time) bool {
+	if len(s.Days) > 0 && !slices.ContainsFunc(s.Days, func(d string) bool {
+		return weekdays[strings.ToLower(d)] == t.Weekday()
+	}) {
+		return false
+	}
+
+	if len(s.Times) > 0 {
+		minute := t.Hour()*60 + t.Minute()
+		if !slices.ContainsFunc(s.Times, func(r string) bool {
+			start, end, err := parseTimeRange(r)
+			return err == nil && inRange(minute, start, end)
+		}) {
+			return false
+		}
+	}
+
+	if s.First > 0 {
+		since := t.Sub(sessionOpen)
+		if since < 0 || since >= time.Duration(s.First) {
+			return false
+		}
+	}
+	return true
+}
+
+// QuietHours holds alerts back during a daily window. Alerts raised in it are
+// delivered as one digest once it ends.
+type QuietHours struct {
+	Start    string `yaml:"start"`              // e.g. "23:00"
+	End      string `yaml:"end"`                // e.g. "07:00"
+	Timezone string `yaml:"timezone,omitempty"` // IANA name, e.g. Asia/Shanghai (default local time)
+}
+
+// Validate checks the window and timezone
+func (q *QuietHours) Validate() error {
+	if _, _, err := parseTimeRange(q.Start + "-" + q.End); err != nil {
+		return err
+	}
+	if q.Timezone != "" {
+		if _, err := time.LoadLocation(q.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", q.Timezone, err)
+		}
+	}
+	return nil
+}
+
+// Contains reports whether t falls within the quiet hours
+func (q *QuietHours) Contains(t time.Time) bool {
+	start, end, err := parseTimeRange(q.Start + "-" + q.End)
+	if err != nil {
+		return false
+	}
+	loc := time.Local
+	if q.Timezone != "" {
+		if l, err := time.LoadLocation(q.Timezone); err == nil {
+			loc = l
+		}
+	}
+	t = t.In(loc)
+	return inRange(t.Hour()*60+t.Minute(), start, end)
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration written in YAML as a string such as "15m", "2h" or "1d"
+type Duration time.Duration
+
+// ParseDuration parses a Go duration string, additionally accepting whole days ("1d")
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return Duration(time.Duration(n) * 24 * time.Hour), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (e.g. 30m, 2h, 1d)", s)
+	}
+	return Duration(d), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := ParseDuration(value.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+// String formats the duration compactly, e.g. "1d", "2h", "1h30m"
+func (d Duration) String() string {
+	td := time.Duration(d)
+	if td > 0 && td%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", td/(24*time.Hour))
+	}
+	s := td.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Band is a distance written either as a percentage ("2%") or an absolute amount ("0.5")
+type Band struct {
+	Value   float64
+	Percent bool
+}
+
+// ParseBand parses a band such as "2%" or "0.5"
+func ParseBand(s string) (Band, error) {
+	s = strings.TrimSpace(s)
+	num, percent := strings.CutSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || v < 0 {
+		return Band{}, fmt.Errorf("invalid band %q (e.g. 2%% or 0.5)", s)
+	}
+	return Band{Value: v, Percent: percent}, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (b *Band) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := ParseBand(value.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	*b = parsed
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (b Band) MarshalYAML() (interface{}, error) {
+	if !b.Percent {
+		return b.Value, nil
+	}
+	return b.String(), nil
+}
+
+// String formats the band as it is written in the config
+func (b Band) String() string {
+	s := strconv.FormatFloat(b.Value, 'f', -1, 64)
+	if b.Percent {
+		s += "%"
+	}
+	return s
+}
+
+// Of returns the band as an amount relative to base. Percentage bands are a
+// fraction of |base| unless the base is itself a percentage, in which case
+// the band is in percentage points.
+func (b Band) Of(base float64, basePercent bool) float64 {
+	if b.Percent && !basePercent {
+		if base < 0 {
+			base = -base
+		}
+		return base * b.Value / 100
+	}
+	return b.Value
+}
+
+// Severity ranks how urgent the alerts of a rule are
+type Severity string
+
+// Alert severities, from least to most urgent
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// ParseSeverity parses info, warning or critical
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToLower(strings.TrimSpace(s))); sev {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return sev, nil
+	}
+	return "", fmt.Errorf("invalid severity %q (use info, warning or critical)", s)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (s *Severity) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := ParseSeverity(value.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	*s = parsed
+	return nil
+}
+
+// Rank orders severities from 0 for info to 2 for critical. An empty severity is info.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityWarning:
+		return 1
+	case SeverityCritical:
+		return 2
+	}
+	return 0
+}
+
+// AtLeast reports whether s is as urgent as min or more
+func (s Severity) AtLeast(min Severity) bool {
+	return s.Rank() >= min.Rank()
+}
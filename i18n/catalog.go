@@ -0,0 +1,43 @@
+package i18n
+
+import (
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+// messages holds the translations of every supported language. English
+// messages are their own keys, so only plural forms are registered for them.
+var messages = newCatalog()
+
+// enPlurals are the English messages whose wording depends on a count, keyed
+// by message with the index of the count argument
+var enPlurals = map[string]struct {
+	arg        int
+	one, other string
+}{
+	"🌙 %d alerts during quiet hours":                        {1, "🌙 %d alert during quiet hours", "🌙 %d alerts during quiet hours"},
+	"\n[%s] Checking %d rules...\n":                         {2, "\n[%s] Checking %d rule...\n", "\n[%s] Checking %d rules...\n"},
+	"  🌙 Quiet hours over, sending %d queued alerts\n":      {1, "  🌙 Quiet hours over, sending %d queued alert\n", "  🌙 Quiet hours over, sending %d queued alerts\n"},
+	"🕒 %s (%d ticks)\n":                                     {2, "🕒 %s (%d tick)\n", "🕒 %s (%d ticks)\n"},
+	"🧪 Backtest %s (%s, %s → %s, %d bars)\n":                {5, "🧪 Backtest %s (%s, %s → %s, %d bar)\n", "🧪 Backtest %s (%s, %s → %s, %d bars)\n"},
+	"🔔 %d new alerts":                                       {1, "🔔 %d new alert", "🔔 %d new alerts"},
+	"  🗂  Sending digest of %d alerts\n":                    {1, "  🗂  Sending digest of %d alert\n", "  🗂  Sending digest of %d alerts\n"},
+	"🔔 Alert history (%d alerts)\n":                         {1, "🔔 Alert history (%d alert)\n", "🔔 Alert history (%d alerts)\n"},
+	"📊 %d alerts\n":                                         {1, "📊 %d alert\n", "📊 %d alerts\n"},
+	"     ⏳ Stale quote, %s old (%d cycles)\n":              {2, "     ⏳ Stale quote, %s old (%d cycle)\n", "     ⏳ Stale quote, %s old (%d cycles)\n"},
+	"%s quotes failing for %d cycles (last update %s): %v":  {2, "%s quotes failing for %d cycle (last update %s): %v", "%s quotes failing for %d cycles (last update %s): %v"},
+	"%s quotes stale for %d cycles (quote from %s, %s old)": {2, "%s quotes stale for %d cycle (quote from %s, %s old)", "%s quotes stale for %d cycles (quote from %s, %s old)"},
+	"over %d trading days":                                  {1, "over %d trading day", "over %d trading days"},
+}
+
+func newCatalog() *catalog.Builder {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	for key, p := range enPlurals {
+		b.Set(language.English, key, plural.Selectf(p.arg, "", "=1", p.one, "other", p.other))
+	}
+	for key, msg := range zhCN {
+		b.SetString(language.SimplifiedChinese, key, msg)
+	}
+	return b
+}
@@ -0,0 +1,109 @@
+// Package i18n translates user-facing text. Messages are keyed by their English
+// format string and looked up in a catalog for the current language, which also
+// decides plural forms and how numbers are formatted.
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Supported languages
+const (
+	English = "en"
+	Chinese = "zh-CN"
+)
+
+// tags maps supported languages to their catalog tags
+var tags = map[string]language.Tag{
+	English: language.English,
+	Chinese: language.SimplifiedChinese,
+}
+
+var (
+	current atomic.Value                    // string
+	printer atomic.Pointer[message.Printer] // Printer for the current language
+)
+
+func init() {
+	SetLanguage(FromEnv())
+}
+
+// Parse normalizes a language name such as "zh", "zh_CN.UTF-8" or "en-US" to a
+// supported language
+func Parse(s string) (string, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, ".@"); i >= 0 {
+		s = s[:i] // Drop the encoding and modifier of locale names
+	}
+	switch {
+	case s == "zh" || strings.HasPrefix(s, "zh-") || strings.HasPrefix(s, "zh_"):
+		return Chinese, true
+	case s == "en" || strings.HasPrefix(s, "en-") || strings.HasPrefix(s, "en_") || s == "c" || s == "posix":
+		return English, true
+	}
+	return "", false
+}
+
+// FromEnv returns the language of LC_ALL, LC_MESSAGES or LANG, whichever is
+// set first, or English if it is not supported
+func FromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			if lang, ok := Parse(v); ok {
+				return lang
+			}
+			break
+		}
+	}
+	return English
+}
+
+// SetLanguage switches all output to lang. Unsupported languages fall back to English.
+func SetLanguage(lang string) {
+	lang, ok := Parse(lang)
+	if !ok {
+		lang = English
+	}
+	current.Store(lang)
+	printer.Store(message.NewPrinter(tags[lang], message.Catalog(messages)))
+}
+
+// Use switches to the configured language, or the environment's if it is empty
+func Use(configured string) {
+	if configured == "" {
+		configured = FromEnv()
+	}
+	SetLanguage(configured)
+}
+
+// Language returns the current language
+func Language() string {
+	return current.Load().(string)
+}
+
+// T translates format and formats it with args like fmt.Sprintf
+func T(format string, args ...any) string {
+	return printer.Load().Sprintf(format, args...)
+}
+
+// Printf translates format and writes it to stdout like fmt.Printf
+func Printf(format string, args ...any) {
+	fmt.Print(T(format, args...))
+}
+
+// Println translates msg and writes it to stdout followed by a newline
+func Println(msg string) {
+	fmt.Println(T(msg))
+}
+
+// Fprintf translates format and writes it to w like fmt.Fprintf
+func Fprintf(w io.Writer, format string, args ...any) {
+	fmt.Fprint(w, T(format, args...))
+}
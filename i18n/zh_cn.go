@@ -0,0 +1,451 @@
+package i18n
+
+// zhCN translates messages to Simplified Chinese, keyed by their English format
+var zhCN = map[string]string{
+	// cmd/alerts.go
+	"Show the alerts fired by watch and dashboard and how they were delivered.\n\n": "显示 watch 和 dashboard 触发的提醒及其发送结果。\n\n",
+	"Options:\n":                             "选项:\n",
+	"\nExamples:\n":                          "\n示例:\n",
+	"Error: use either --json or --csv\n":    "错误: --json 和 --csv 只能选一个\n",
+	"Error: %v\n":                            "错误: %v\n",
+	"Error: invalid --from: %v\n":            "错误: --from 无效: %v\n",
+	"Error: invalid --to: %v\n":              "错误: --to 无效: %v\n",
+	"Error loading config: %v\n":             "加载配置失败: %v\n",
+	"Error opening alert history: %v\n":      "打开提醒记录失败: %v\n",
+	"Error querying alert history: %v\n":     "查询提醒记录失败: %v\n",
+	"Error writing CSV: %v\n":                "写入 CSV 失败: %v\n",
+	"No alerts recorded for this selection.": "没有符合条件的提醒记录。",
+	"🔔 Alert history (%d alerts)\n":          "🔔 提醒记录 (%d 条)\n",
+
+	// cmd/backtest.go
+	"Replay historical candles through a symbol's rules and report the alerts they would have sent.\n\n": "用历史K线回放某个股票的规则，报告本会发送的提醒。\n\n",
+	"Error: --symbol is required\n\n":                                               "错误: 缺少 --symbol\n\n",
+	"Error: invalid --resolution %q (use D, 60, 30, 15, 5 or 1)\n":                  "错误: 无效的 --resolution %q (可用 D、60、30、15、5 或 1)\n",
+	"Error: no rules found for %s\n":                                                "错误: 未找到 %s 的规则\n",
+	"Add one with: stock-ping config add --symbol %s --price-above 200\n":           "添加规则: stock-ping config add --symbol %s --price-above 200\n",
+	"Error fetching candles: %v\n":                                                  "获取K线失败: %v\n",
+	"🧪 Backtest %s (%s, %s → %s, %d bars)\n":                                        "🧪 回测 %s (%s, %s → %s, %d 根K线)\n",
+	"No alerts would have been sent.":                                               "不会发送任何提醒。",
+	"Time             Rule                Price      +1d      +5d     +20d  Reason": "时间             规则                 价格      +1d      +5d     +20d  原因",
+	"📊 %d alerts\n": "📊 %d 次提醒\n",
+	"Horizon   Count      Mean    Median  Win rate      Best     Worst": "周期       次数      均值    中位数      胜率      最好      最差",
+
+	// cmd/config.go
+	"Commands:\n": "命令:\n",
+	"  list                 List all monitoring rules\n":                           "  list                 列出所有监控规则\n",
+	"  add                  Add a new monitoring rule (or update one with --id)\n": "  add                  添加监控规则 (或用 --id 更新规则)\n",
+	"  portfolio            Add a portfolio rule (or update one with --id)\n":      "  portfolio            添加组合规则 (或用 --id 更新规则)\n",
+	"  remove               Remove a monitoring rule by ID\n":                      "  remove               按 ID 删除监控规则\n",
+	"  enable               Enable a monitoring rule by ID\n":                      "  enable               按 ID 启用监控规则\n",
+	"  disable              Disable a monitoring rule by ID\n":                     "  disable              按 ID 停用监控规则\n",
+	"No monitoring rules configured.":                                              "尚未配置监控规则。",
+	"Add rules with: stock-ping config add --symbol AAPL --price-above 200":        "添加规则: stock-ping config add --symbol AAPL --price-above 200",
+	"📋 Monitoring Rules (%d)\n":                                                    "📋 监控规则 (%d)\n",
+	" (disabled)":                                                                  " (已停用)",
+	"   • Price above $%.2f\n":                                                     "   • 价格高于 $%.2f\n",
+	"   • Price below $%.2f\n":                                                     "   • 价格低于 $%.2f\n",
+	"   • Up more than %.2f%%\n":                                                   "   • 涨幅超过 %.2f%%\n",
+	"   • Down more than %.2f%%\n":                                                 "   • 跌幅超过 %.2f%%\n",
+	"   • Limit up/down\n":                                                         "   • 涨停/跌停\n",
+	"   • Within %.2f%% of limit up/down\n":                                        "   • 距涨停/跌停 %.2f%% 以内\n",
+	"   • Gain above %.2f%% (take profit)\n":                                       "   • 浮盈超过 %.2f%% (止盈)\n",
+	"   • Loss below %.2f%% (stop loss)\n":                                         "   • 浮亏超过 %.2f%% (止损)\n",
+	"   • Position P/L above $%.2f\n":                                              "   • 持仓盈亏高于 $%.2f\n",
+	"   • Position P/L below $%.2f\n":                                              "   • 持仓盈亏低于 $%.2f\n",
+	"   • Trailing stop: %.2f%% below the peak\n":                                  "   • 移动止损: 自高点回落 %.2f%%\n",
+	"   • Trailing stop: $%.2f below the peak\n":                                   "   • 移动止损: 自高点回落 $%.2f\n",
+	"   • Gap up more than %.2f%%\n":                                               "   • 高开超过 %.2f%%\n",
+	"   • Gap down more than %.2f%%\n":                                             "   • 低开超过 %.2f%%\n",
+	"   • New intraday high %s after the open\n":                                   "   • 开盘 %s 后创日内新高\n",
+	"   • New intraday low %s after the open\n":                                    "   • 开盘 %s 后创日内新低\n",
+	"   • Breaks the previous day's high\n":                                        "   • 突破昨日最高价\n",
+	"   • Breaks the previous day's low\n":                                         "   • 跌破昨日最低价\n",
+	"   • %d-day high\n":                                                           "   • 创 %d 日新高\n",
+	"   • %d-day low\n":                                                            "   • 创 %d 日新低\n",
+	"   • Change %s above %.2f%%\n":                                                "   • %s涨跌幅高于 %.2f%%\n",
+	"   • Change %s below %.2f%%\n":                                                "   • %s涨跌幅低于 %.2f%%\n",
+	"   • Pair: %s vs %s (%s)\n":                                                   "   • 配对: %s vs %s (%s)\n",
+	"     - above %g\n":                                                            "     - 高于 %g\n",
+	"     - below %g\n":                                                            "     - 低于 %g\n",
+	"     - %[2]g standard deviations from the %[1]d-day mean\n":                   "     - 偏离 %d 日均值 %g 个标准差\n",
+	"daily":                             "日线",
+	"%s-minute":                         "%s 分钟线",
+	"   • Price crosses SMA(%d) (%s)\n": "   • 价格穿越 SMA(%d) (%s)\n",
+	"   • SMA(%d)/SMA(%d) golden/death cross (%s)\n":                "   • SMA(%d)/SMA(%d) 金叉/死叉 (%s)\n",
+	"   • MACD crosses its signal line (%s)\n":                      "   • MACD 穿越信号线 (%s)\n",
+	"   • RSI(%d) in/out of overbought %.0f / oversold %.0f (%s)\n": "   • RSI(%d) 进出超买 %.0f / 超卖 %.0f (%s)\n",
+	"   • When: %s\n":                                                     "   • 条件: %s\n",
+	"   🕒 Active days: %s\n":                                              "   🕒 生效日: %s\n",
+	"   🕒 Active times: %s\n":                                             "   🕒 生效时段: %s\n",
+	"   🕒 Only within %s after the open\n":                                "   🕒 仅开盘后 %s 内\n",
+	"💼 Portfolio Rules (%d)\n":                                            "💼 组合规则 (%d)\n",
+	"   • Total value above $%.2f\n":                                      "   • 总市值高于 $%.2f\n",
+	"   • Total value below $%.2f\n":                                      "   • 总市值低于 $%.2f\n",
+	"   • Total P/L above $%.2f\n":                                        "   • 总盈亏高于 $%.2f\n",
+	"   • Total P/L below $%.2f\n":                                        "   • 总盈亏低于 $%.2f\n",
+	"   • Total return above %.2f%%\n":                                    "   • 总收益率高于 %.2f%%\n",
+	"   • Total return below %.2f%%\n":                                    "   • 总收益率低于 %.2f%%\n",
+	"   • Up more than %.2f%% today\n":                                    "   • 今日涨幅超过 %.2f%%\n",
+	"   • Down more than %.2f%% today\n":                                  "   • 今日跌幅超过 %.2f%%\n",
+	"Config file: %s\n":                                                   "配置文件: %s\n",
+	"   ⏱ Cooldown: %s\n":                                                 "   ⏱ 冷却: %s\n",
+	"   ⏱ Re-arms after moving back %s\n":                                 "   ⏱ 回撤 %s 后重新提醒\n",
+	"   ⏱ Repeats every %s while met\n":                                   "   ⏱ 持续满足时每 %s 重复提醒\n",
+	"   🚦 Severity: %s\n":                                                 "   🚦 级别: %s\n",
+	"   🚦 Severity of %s: %s\n":                                           "   🚦 %s 的级别: %s\n",
+	"Rule %s not found\n":                                                 "未找到规则 %s\n",
+	"Rule %s belongs to %s, not %s\n":                                     "规则 %s 属于 %s, 而不是 %s\n",
+	"Error: invalid --new-day-high-after: %v\n":                           "错误: --new-day-high-after 无效: %v\n",
+	"Error: invalid --new-day-low-after: %v\n":                            "错误: --new-day-low-after 无效: %v\n",
+	"Error: --new-high-days and --new-low-days must be positive\n":        "错误: --new-high-days 和 --new-low-days 必须为正数\n",
+	"Error: invalid change: %v\n":                                         "错误: 区间涨跌幅配置无效: %v\n",
+	"Error: invalid --when expression: %v\n":                              "错误: --when 表达式无效: %v\n",
+	"Error: invalid --cross-price-sma: %v\n":                              "错误: --cross-price-sma 无效: %v\n",
+	"Error: invalid --cross-sma: %v\n":                                    "错误: --cross-sma 无效: %v\n",
+	"Error: invalid crossovers: %v\n":                                     "错误: 指标交叉配置无效: %v\n",
+	"Error: invalid pair: %v\n":                                           "错误: 配对配置无效: %v\n",
+	"Error: invalid --active-first: %v\n":                                 "错误: --active-first 无效: %v\n",
+	"Error: invalid active schedule: %v\n":                                "错误: 生效时间无效: %v\n",
+	"Error: at least one condition is required\n\n":                       "错误: 至少需要一个条件\n\n",
+	"Error adding rule: %v\n":                                             "添加规则失败: %v\n",
+	"Error saving config: %v\n":                                           "保存配置失败: %v\n",
+	"✅ Updated rule %s for %s\n":                                          "✅ 已更新 %[2]s 的规则 %[1]s\n",
+	"✅ Added rule %s for %s\n":                                            "✅ 已为 %[2]s 添加规则 %[1]s\n",
+	"Alerts on the holdings as a whole, checked after every refresh.\n\n": "针对全部持仓的提醒, 每轮刷新后检查。\n\n",
+	"Portfolio rule %s not found\n":                                       "未找到组合规则 %s\n",
+	"✅ Updated portfolio rule %s\n":                                       "✅ 已更新组合规则 %s\n",
+	"✅ Added portfolio rule %s\n":                                         "✅ 已添加组合规则 %s\n",
+	"Error: --id or --symbol is required\n\n":                             "错误: 需要 --id 或 --symbol\n\n",
+	"No rules for %s found\n":                                             "未找到 %s 的规则\n",
+	"✅ Removed rule %s\n":                                                 "✅ 已删除规则 %s\n",
+	"Error: --id is required\n\n":                                         "错误: 缺少 --id\n\n",
+	"✅ Rule %s enabled\n":                                                 "✅ 已启用规则 %s\n",
+	"   Its snooze or disable from the dashboard was lifted":              "   已解除在面板中设置的暂停或停用",
+	"✅ Rule %s disabled\n":                                                "✅ 已停用规则 %s\n",
+
+	// cmd/dashboard.go
+	"Launch interactive TUI dashboard for stock monitoring.\n":          "启动交互式 TUI 监控面板。\n",
+	"Supports hot-reload: edit ~/.stock-ping.yaml to update rules.\n\n": "支持热加载: 编辑 ~/.stock-ping.yaml 即可更新规则。\n\n",
+	"Keybindings:\n":                                  "快捷键:\n",
+	"  r       Refresh all stocks\n":                  "  r       刷新所有股票\n",
+	"  q       Quit\n":                                "  q       退出\n",
+	"  ?       Help\n":                                "  ?       帮助\n",
+	"Error: Finnhub API key not configured.\n":        "错误: 未配置 Finnhub API Key。\n",
+	"Please add your API key to ~/.stock-ping.yaml\n": "请在 ~/.stock-ping.yaml 中添加 API Key\n",
+	"No monitoring rules configured.\n":               "尚未配置监控规则。\n",
+	"Add rules with: stock-ping config add --symbol AAPL --price-above 200\n": "添加规则: stock-ping config add --symbol AAPL --price-above 200\n",
+	"Warning: Failed to setup config watcher: %v\n":                           "警告: 配置文件监听启动失败: %v\n",
+	"Error running TUI: %v\n":                                                 "运行 TUI 失败: %v\n",
+
+	// cmd/history.go
+	"Show quotes recorded by watch and dashboard.\n\n": "显示 watch 和 dashboard 记录的行情。\n\n",
+	"Error opening tick history: %v\n":                 "打开行情记录失败: %v\n",
+	"Error querying tick history: %v\n":                "查询行情记录失败: %v\n",
+	"No ticks recorded for %s between %s and %s.\n":    "%s 在 %s 至 %s 之间没有行情记录。\n",
+	"🕒 %s (%d ticks)\n":                                "🕒 %s (%d 条行情)\n",
+
+	// cmd/holding.go
+	"  list                 List all holdings\n":                                           "  list                 列出所有持仓\n",
+	"  add                  Add or update a holding\n":                                     "  add                  添加或更新持仓\n",
+	"  remove               Remove a holding\n":                                            "  remove               删除持仓\n",
+	"No holdings configured.":                                                              "尚未配置持仓。",
+	"Add holdings with: stock-ping holding add --symbol AAPL --quantity 100 --cost 150.50": "添加持仓: stock-ping holding add --symbol AAPL --quantity 100 --cost 150.50",
+	"📊 Holdings (%d)\n":                                                                    "📊 持仓 (%d)\n",
+	"   • Quantity: %.2f\n":                                                                "   • 数量: %.2f\n",
+	"   • Cost price: $%.2f\n":                                                             "   • 成本价: $%.2f\n",
+	"   • Cost basis: $%.2f\n":                                                             "   • 持仓成本: $%.2f\n",
+	"Total cost basis: $%.2f\n":                                                            "总持仓成本: $%.2f\n",
+	"Error: --quantity must be greater than 0\n\n":                                         "错误: --quantity 必须大于 0\n\n",
+	"Error: --cost must be greater than 0\n\n":                                             "错误: --cost 必须大于 0\n\n",
+	"ℹ️  Existing holding found: %.2f shares @ $%.2f\n":                                    "ℹ️  已有持仓: %.2f 股 @ $%.2f\n",
+	"   Adding: %.2f shares @ $%.2f\n":                                                     "   加仓: %.2f 股 @ $%.2f\n",
+	"   New Position: %.2f shares @ $%.2f\n":                                               "   新持仓: %.2f 股 @ $%.2f\n",
+	"ℹ️  No monitoring rule found for %s. Creating one...\n":                               "ℹ️  %s 还没有监控规则, 正在创建...\n",
+	"⚠️  Failed to fetch symbol details: %v. Using defaults.\n":                            "⚠️  获取股票信息失败: %v, 使用默认值。\n",
+	"✅ Found details: %s (%s)\n":                                                           "✅ 已找到: %s (%s)\n",
+	"⚠️  Failed to add rule: %v\n":                                                         "⚠️  添加规则失败: %v\n",
+	"✅ Updated take-profit/stop-loss rule %s\n":                                            "✅ 已更新止盈/止损规则 %s\n",
+	"⚠️  Failed to add take-profit/stop-loss rule: %v\n":                                   "⚠️  添加止盈/止损规则失败: %v\n",
+	"✅ Added take-profit/stop-loss rule %s\n":                                              "✅ 已添加止盈/止损规则 %s\n",
+	"✅ Added holding for %s: %.2f shares @ $%.2f (total cost: $%.2f)\n":                    "✅ 已添加 %s 持仓: %.2f 股 @ $%.2f (总成本: $%.2f)\n",
+	"Holding for %s not found\n":                                                           "未找到 %s 的持仓\n",
+	"✅ Removed holding for %s\n":                                                           "✅ 已删除 %s 的持仓\n",
+
+	// cmd/once.go
+	"Query current price for a single stock.\n\n": "查询单只股票的当前价格。\n\n",
+	"Example:\n": "示例:\n",
+	"Please add your API key to ~/.stock-ping.yaml:\n": "请在 ~/.stock-ping.yaml 中添加 API Key:\n",
+	"Error fetching quote: %v\n":                       "获取行情失败: %v\n",
+
+	// cmd/rule.go
+	"  test                 Dry-run a symbol's rules against a made-up quote\n":                "  test                 用模拟行情试运行某个股票的规则\n",
+	"Run a symbol's rules against a made-up quote and show what would fire. Nothing is sent\n": "用模拟行情运行某个股票的规则，显示哪些会触发。不会发送任何通知，\n",
+	"and alert state is not touched.\n\n":                                                      "也不会改动提醒状态。\n\n",
+	"Error reading quote file: %v\n":                                                           "读取行情文件失败: %v\n",
+	"Error parsing quote file: %v\n":                                                           "解析行情文件失败: %v\n",
+	"Error: a positive --price (or CurrentPrice in --quote-file) is required\n":                "错误: 需要正数的 --price (或 --quote-file 中的 CurrentPrice)\n",
+	"Error: invalid --at: %v\n":                                                                "错误: 无效的 --at: %v\n",
+	"🧪 %s $%.2f (%+.2f%%) prev close $%.2f\n":                                                  "🧪 %s $%.2f (%+.2f%%) 昨收 $%.2f\n",
+	"   🕒 Outside the rule's active schedule, not evaluated":                                   "   🕒 不在规则的生效时段内，未评估",
+	"   ✓ No condition met":                                                                    "   ✓ 没有满足的条件",
+	"   🛑 Trailing stop $%.2f (peak $%.2f)\n":                                                  "   🛑 移动止损 $%.2f (高点 $%.2f)\n",
+	"   📱 %s notification (not sent):\n":                                                       "   📱 %s 级通知 (未发送):\n",
+	"   🗂  Batched into the digest instead of sent on its own":                                 "   🗂  将合并到汇总通知中，不单独发送",
+	"⚠️  Failed to fetch candles (%s): %v\n":                                                   "⚠️  获取K线失败 (%s): %v\n",
+	"⚠️  Failed to fetch pair leg %s: %v\n":                                                    "⚠️  获取配对标的 %s 失败: %v\n",
+	"⚠️  Failed to fetch candles for %s: %v\n":                                                 "⚠️  获取 %s 的K线失败: %v\n",
+
+	// cmd/watch.go
+	"%dh %dm": "%d小时%d分钟",
+	"%dm":     "%d分钟",
+	"Continuously monitor stocks based on configured rules.\n": "根据配置的规则持续监控股票。\n",
+	"Press Ctrl+C to stop.\n\n":                                "按 Ctrl+C 停止。\n\n",
+	"🔔 Stock Monitor Started (interval: %ds)\n":                "🔔 股票监控已启动 (间隔: %d 秒)\n",
+	"⚠️  Warning: Bark not configured, notifications disabled": "⚠️  警告: 未配置 Bark, 通知已停用",
+	"\n💤 US market closed, resuming in %s\n":                   "\n💤 美股休市中，将在 %s 后自动恢复监控\n",
+	"   Next open: %s (US Eastern)\n":                          "   下次开盘时间: %s (美东时间)\n",
+	"   Waiting for the open...":                               "   程序将继续运行，等待开盘...",
+	"\n🔔 US market open, monitoring resumed!":                  "\n🔔 美股开盘，恢复监控!",
+	"\n👋 Shutting down...":                                     "\n👋 正在退出...",
+	"\n💤 US market closed for the day, resuming in %s\n":       "\n💤 美股收盘，将在 %s 后自动恢复监控\n",
+	"Warning: tick history disabled: %v\n":                     "警告: 行情记录已停用: %v\n",
+	"Warning: alert history disabled: %v\n":                    "警告: 提醒记录已停用: %v\n",
+	"Warning: alert state will not persist: %v\n":              "警告: 提醒状态不会保存: %v\n",
+	"\n[%s] Checking %d rules...\n":                            "\n[%s] 正在检查 %d 条规则...\n",
+	"  %s ❌ Error: %v\n":                                       "  %s ❌ 错误: %v\n",
+	"  %s ⚠️  Failed to record tick: %v\n":                     "  %s ⚠️  记录行情失败: %v\n",
+	"Warning: %v\n": "警告: %v\n",
+	"  %s ⚠️  Failed to fetch candles (%s): %v\n":                "  %s ⚠️  获取 K 线失败 (%s): %v\n",
+	"  %s 📼 Using daily bars from recorded quotes for changes\n": "  %s 📼 涨跌幅条件改用本地记录的日线\n",
+	"  %s ⚠️  Failed to fetch pair leg %s: %v\n":                 "  %s ⚠️  获取配对股票 %s 失败: %v\n",
+	"  %s ⚠️  Failed to fetch candles for %s: %v\n":              "  %s ⚠️  获取 %s 的 K 线失败: %v\n",
+	"  %s ⚠️  Failed to save alert state: %v\n":                  "  %s ⚠️  保存提醒状态失败: %v\n",
+	"     🛑 [%s] Trailing stop $%.2f (peak $%.2f)\n":             "     🛑 [%s] 移动止损 $%.2f (高点 $%.2f)\n",
+	"     🗂  Added to the digest":                                "     🗂  已加入汇总通知",
+	"     ❌ Failed to send notification: %v\n":                   "     ❌ 发送通知失败: %v\n",
+	"     📱 Bark notification sent":                              "     📱 已发送 Bark 通知",
+	"     ⚠️  Failed to record alerts: %v\n":                     "     ⚠️  记录提醒失败: %v\n",
+	"  🗂  Sending digest of %d alerts\n":                         "  🗂  发送汇总通知 (%d 条提醒)\n",
+	"     🔕 [%s] Disabled from the dashboard\n":                  "     🔕 [%s] 已在面板中停用\n",
+	"     💤 [%s] Snoozed until %s\n":                             "     💤 [%s] 已暂停至 %s\n",
+	"     ⏳ Stale quote, %s old (%d cycles)\n":                   "     ⏳ 行情已 %s 未更新 (连续 %d 个周期)\n",
+	"  🌙 ⚠️  Failed to save alert state: %v\n":                   "  🌙 ⚠️  保存提醒状态失败: %v\n",
+	"  🌙 Quiet hours over, sending %d queued alerts\n":           "  🌙 静默时段结束, 汇总 %d 条提醒\n",
+	"  💼 ⚠️  Failed to fetch %s: %v\n":                           "  💼 ⚠️  获取 %s 失败: %v\n",
+	"  💼 ⚠️  Failed to save alert state: %v\n":                   "  💼 ⚠️  保存提醒状态失败: %v\n",
+	"  💼 Portfolio rules skipped, no quote for %s\n":             "  💼 已跳过组合规则, 缺少 %s 的行情\n",
+	"  💼 Portfolio $%.2f P/L %+.2f%% today %+.2f%%\n":            "  💼 组合 $%.2f 盈亏 %+.2f%% 今日 %+.2f%%\n",
+
+	// main.go
+	"Unknown command: %s\n\n":                         "未知命令: %s\n\n",
+	"stock-ping - A simple stock monitoring CLI tool": "stock-ping - 简洁的股票监控命令行工具",
+	"Usage:":    "用法:",
+	"Commands:": "命令:",
+	"  add [options]    Quickly add a new monitoring rule":                  "  add [options]    快速添加监控规则",
+	"  once <SYMBOL>    Query current price for a single stock":             "  once <SYMBOL>    查询单只股票的当前价格",
+	"  watch            Continuously monitor stocks (text mode)":            "  watch            持续监控股票 (文本模式)",
+	"  dashboard        Interactive TUI dashboard with hot-reload":          "  dashboard        支持热加载的交互式终端面板",
+	"  holding          Manage portfolio holdings (add/list/remove)":        "  holding          管理持仓 (add/list/remove)",
+	"  config           Manage monitoring rules (add/list/remove)":          "  config           管理监控规则 (add/list/remove)",
+	"  history <SYMBOL> Show locally recorded quote history":                "  history <SYMBOL> 查看本地记录的行情历史",
+	"  alerts           Show and export the history of fired alerts":        "  alerts           查看和导出提醒记录",
+	"  backtest         Replay historical candles through a symbol's rules": "  backtest         用历史K线回放某个股票的规则",
+	"  rule test        Dry-run a symbol's rules against a made-up quote":   "  rule test        用模拟行情试运行某个股票的规则",
+	"  version          Show version information":                           "  version          显示版本信息",
+	"  help             Show this help message":                             "  help             显示此帮助信息",
+	"Examples:":                                "示例:",
+	"Configuration:":                           "配置:",
+	"  Config file: ~/.stock-ping.yaml":        "  配置文件: ~/.stock-ping.yaml",
+	"  Data dir:    ~/.local/share/stock-ping": "  数据目录: ~/.local/share/stock-ping",
+	"For more information, visit: https://github.com/congregalis/stock-ping": "更多信息请访问: https://github.com/congregalis/stock-ping",
+
+	// rule/batch.go
+	"🔔 %d new alerts": "🔔 %d 条新提醒",
+
+	// rule/change.go
+	"over %d trading days": "近 %d 个交易日",
+	"since %s":             "自 %s 以来",
+	"from the %d-day high": "相对 %d 日最高价",
+	"from the %d-day low":  "相对 %d 日最低价",
+	"Change %+.2f%% %s, above %.2f%% (reference $%.2f on %s)": "%[2]s涨跌幅 %+.2[1]f%% 高于 %.2[3]f%% (基准价 $%.2[4]f，%[5]s)",
+	"Change %+.2f%% %s, below %.2f%% (reference $%.2f on %s)": "%[2]s涨跌幅 %+.2[1]f%% 低于 %.2[3]f%% (基准价 $%.2[4]f，%[5]s)",
+
+	// rule/crossover.go
+	" (%s-minute bars)":                                         " (%s 分钟线)",
+	"Price crossed above SMA(%d) $%.2f%s":                       "价格上穿 SMA(%d) $%.2f%s",
+	"Price crossed below SMA(%d) $%.2f%s":                       "价格下穿 SMA(%d) $%.2f%s",
+	"Golden cross: SMA(%d) crossed above SMA(%d)%s":             "金叉: SMA(%d) 上穿 SMA(%d)%s",
+	"Death cross: SMA(%d) crossed below SMA(%d)%s":              "死叉: SMA(%d) 下穿 SMA(%d)%s",
+	"MACD bullish cross: MACD %.3f crossed above signal %.3f%s": "MACD 金叉: MACD %.3f 上穿信号线 %.3f%s",
+	"MACD bearish cross: MACD %.3f crossed below signal %.3f%s": "MACD 死叉: MACD %.3f 下穿信号线 %.3f%s",
+	"RSI(%d) %.2f entered overbought (> %.0f)%s":                "RSI(%d) %.2f 进入超买区 (> %.0f)%s",
+	"RSI(%d) %.2f left overbought (< %.0f)%s":                   "RSI(%d) %.2f 离开超买区 (< %.0f)%s",
+	"RSI(%d) %.2f entered oversold (< %.0f)%s":                  "RSI(%d) %.2f 进入超卖区 (< %.0f)%s",
+	"RSI(%d) %.2f left oversold (> %.0f)%s":                     "RSI(%d) %.2f 离开超卖区 (> %.0f)%s",
+
+	// rule/evaluator.go
+	"📊 %s alert":                                                "📊 %s 触发提醒",
+	"Price: $%.2f (%+.2f%%)\n":                                  "价格: $%.2f (%+.2f%%)\n",
+	"Position P/L: %s":                                          "持仓盈亏: %s",
+	"Price $%.2f above $%.2f":                                   "价格 $%.2f 超过 $%.2f",
+	"Price $%.2f below $%.2f":                                   "价格 $%.2f 低于 $%.2f",
+	"Up %.2f%%, more than %.2f%%":                               "涨幅 %.2f%% 超过 %.2f%%",
+	"Down %.2f%%, more than %.2f%%":                             "跌幅 %.2f%% 超过 %.2f%%",
+	"Limit up ¥%.2f":                                            "涨停 ¥%.2f",
+	"Limit down ¥%.2f":                                          "跌停 ¥%.2f",
+	"Limit up ¥%.2f only %.2f%% away":                           "距涨停 ¥%.2f 仅 %.2f%%",
+	"Limit down ¥%.2f only %.2f%% away":                         "距跌停 ¥%.2f 仅 %.2f%%",
+	"Gain %.2f%% above %.2f%%":                                  "浮盈 %.2f%% 超过 %.2f%%",
+	"Loss %.2f%% below %.2f%%":                                  "浮亏 %.2f%% 超过 %.2f%%",
+	"Position P/L %s above %s":                                  "持仓盈亏 %s 超过 %s",
+	"Position P/L %s below %s":                                  "持仓盈亏 %s 低于 %s",
+	"Fell from peak $%.2f by %.2f%%, below trailing stop $%.2f": "自高点 $%.2f 回落 %.2f%%，跌破移动止损 $%.2f",
+	"Fell from peak $%.2f by $%.2f, below trailing stop $%.2f":  "自高点 $%.2f 回落 $%.2f，跌破移动止损 $%.2f",
+	"Met %s": "满足 %s",
+
+	// rule/feed.go
+	"%s quotes recovered after %s":                          "%s 行情已恢复, 异常持续 %s",
+	"%s quotes failing for %d cycles (last update %s): %v":  "%s 行情连续 %d 个周期获取失败 (最后更新 %s): %v",
+	"%s quotes stale for %d cycles (quote from %s, %s old)": "%s 行情连续 %d 个周期未更新 (行情时间 %s, 已过 %s)",
+	"never":                  "从未",
+	"✅ Quote feed recovered": "✅ 行情数据已恢复",
+	"📡 Quote feed stale":     "📡 行情数据未更新",
+	"📡 Quote feed failing":   "📡 行情获取失败",
+
+	// rule/pair.go
+	"premium":        "溢价率",
+	"spread":         "价差",
+	"ratio":          "比价",
+	"%s %s above %s": "%s %s 超过 %s",
+	"%s %s below %s": "%s %s 低于 %s",
+	"%s %s is %[5]+.2f standard deviations from its %[3]d-day mean %[4]s": "%s %s 偏离 %d 日均值 %s 达 %+.2f 个标准差",
+
+	// rule/portfolio.go
+	"Portfolio":                                     "组合",
+	"market value":                                  "市值",
+	"today's P/L":                                   "今日盈亏",
+	"position P/L":                                  "持仓盈亏",
+	"💼 Portfolio alert: %s":                         "💼 组合提醒: %s",
+	"Total value: $%.2f\n":                          "总市值: $%.2f\n",
+	"Total P/L: %s":                                 "总盈亏: %s",
+	"\nToday: %s":                                   "\n今日: %s",
+	"Top contributors to %s:\n":                     "%s主要贡献:\n",
+	"Portfolio value $%.2f above $%.2f":             "组合市值 $%.2f 超过 $%.2f",
+	"Portfolio value $%.2f below $%.2f":             "组合市值 $%.2f 低于 $%.2f",
+	"Portfolio P/L %s above %s":                     "组合盈亏 %s 超过 %s",
+	"Portfolio P/L %s below %s":                     "组合盈亏 %s 低于 %s",
+	"Portfolio return %.2f%% above %.2f%%":          "组合收益率 %.2f%% 超过 %.2f%%",
+	"Portfolio return %.2f%% below %.2f%%":          "组合收益率 %.2f%% 低于 %.2f%%",
+	"Portfolio up %.2f%% today, more than %.2f%%":   "组合今日涨幅 %.2f%% 超过 %.2f%%",
+	"Portfolio down %.2f%% today, more than %.2f%%": "组合今日跌幅 %.2f%% 超过 %.2f%%",
+
+	// rule/ranges.go
+	"Gap up %.2f%%, more than %.2f%% (open $%.2f, previous close $%.2f)":   "高开 %.2f%% 超过 %.2f%% (开盘 $%.2f，昨收 $%.2f)",
+	"Gap down %.2f%%, more than %.2f%% (open $%.2f, previous close $%.2f)": "低开 %.2f%% 超过 %.2f%% (开盘 $%.2f，昨收 $%.2f)",
+	"New intraday high $%.2f (%s after the open)":                          "创日内新高 $%.2f (开盘 %s 后)",
+	"New intraday low $%.2f (%s after the open)":                           "创日内新低 $%.2f (开盘 %s 后)",
+	"Price $%.2f broke above the previous high $%.2f":                      "价格 $%.2f 突破昨日最高价 $%.2f",
+	"Price $%.2f broke below the previous low $%.2f":                       "价格 $%.2f 跌破昨日最低价 $%.2f",
+	"Price $%.2f at a %d-day high (previous high $%.2f)":                   "价格 $%.2f 创 %d 日新高 (前高 $%.2f)",
+	"Price $%.2f at a %d-day low (previous low $%.2f)":                     "价格 $%.2f 创 %d 日新低 (前低 $%.2f)",
+
+	// rule/tracker.go
+	"🌙 %d alerts during quiet hours": "🌙 静默时段内的 %d 条提醒",
+
+	// stock/finnhub.go
+	"📈 %s\n   Price: $%.2f\n   Change: %s$%.2f (%+.2f%%)\n   Today: $%.2f ~ $%.2f": "📈 %s\n   价格: $%.2f\n   涨跌: %s$%.2f (%+.2f%%)\n   今日: $%.2f ~ $%.2f",
+	"\n   Limit up/down: ¥%.2f / ¥%.2f":                                            "\n   涨停/跌停: ¥%.2f / ¥%.2f",
+
+	// store/alerts.go
+	"sent":           "已发送",
+	"failed":         "发送失败",
+	"not sent":       "未发送",
+	" (digest)":      " (汇总)",
+	" (quiet hours)": " (静默时段)",
+
+	// tui/keys.go
+	"refresh":                   "刷新",
+	"quit":                      "退出",
+	"help":                      "帮助",
+	"sort order":                "排序",
+	"details":                   "详情",
+	"back":                      "返回",
+	"portfolio":                 "投资组合",
+	"dashboard":                 "行情",
+	"alerts":                    "提醒",
+	"privacy mode":              "隐私模式",
+	"acknowledge":               "确认",
+	"snooze":                    "暂停",
+	"snooze until next session": "暂停至下个交易时段",
+	"disable":                   "停用",
+	"resume":                    "恢复",
+
+	// tui/model.go
+	"Symbol":                        "代码",
+	"Price":                         "价格",
+	"Change":                        "涨跌",
+	"Open":                          "开盘",
+	"Day Range":                     "日内区间",
+	"Prev Close":                    "昨收",
+	"Trailing Stop":                 "移动止损",
+	"Updated":                       "更新",
+	"Quantity":                      "数量",
+	"Cost":                          "成本",
+	"P/L":                           "盈亏",
+	"Time":                          "时间",
+	"Severity":                      "级别",
+	"Alert":                         "提醒",
+	"Delivery":                      "发送",
+	"⚠️  Failed to record tick: %v": "⚠️  记录行情失败: %v",
+	"📼 %s: using daily bars from recorded quotes for changes": "📼 %s: 涨跌幅条件改用本地记录的日线",
+	"🙈 Privacy Mode: ON":                          "🙈 隐私模式: 开",
+	"🐵 Privacy Mode: OFF":                         "🐵 隐私模式: 关",
+	"Refreshing...":                               "刷新中...",
+	"Descending":                                  "降序",
+	"Ascending":                                   "升序",
+	"Sorted: %s":                                  "排序: %s",
+	"🔄 Config reloaded":                           "🔄 配置已重新加载",
+	"⚠️ Failed to save alert state: %v":           "⚠️ 保存提醒状态失败: %v",
+	"🔔 Alerts of %s resumed":                      "🔔 %s 的提醒已恢复",
+	"Nothing to resume for %s":                    "%s 没有需要恢复的提醒",
+	"No alerts met for %s":                        "%s 当前没有触发的提醒",
+	"✅ Alerts of %s acknowledged":                 "✅ 已确认 %s 的提醒",
+	"🔕 Alerts of %s disabled until resumed":       "🔕 %s 的提醒已停用, 恢复前不再提醒",
+	"💤 Alerts of %s snoozed until %s":             "💤 %s 的提醒已暂停至 %s",
+	"⚠️ Portfolio rules skipped, no quote for %s": "⚠️ 已跳过组合规则, 缺少 %s 的行情",
+	"⚠️ Failed to send notification: %v":          "⚠️ 发送通知失败: %v",
+	"⚠️ Failed to record alerts: %v":              "⚠️ 记录提醒失败: %v",
+	"$%.2f (peak $%.2f)":                          "$%.2f (高点 $%.2f)",
+	"👋 Goodbye!\n":                                "👋 再见!\n",
+	"Unknown view":                                "未知视图",
+	"%dh%dm":                                      "%d小时%d分钟",
+
+	// tui/view_alerts.go
+	"🔔 Alert History":                 "🔔 提醒记录",
+	"Alert history is not available.": "提醒记录不可用。",
+	"Error loading alerts: %v":        "加载提醒失败: %v",
+	"No alerts in the last %d days.":  "最近 %d 天没有提醒。",
+	"Alerts: %d":                      "提醒: %d",
+	"Last %d days":                    "最近 %d 天",
+
+	// tui/view_dashboard.go
+	"Interval: %ds": "间隔: %d秒",
+	"Stocks: %d":    "股票: %d",
+	"Last: %s":      "最近: %s",
+
+	// tui/view_portfolio.go
+	"📈 Portfolio":  "📈 投资组合",
+	"TOTAL VALUE":  "总市值",
+	"TOTAL COST":   "总成本",
+	"TOTAL P/L":    "总盈亏",
+	"Holdings: %d": "持仓: %d",
+
+	// tui/view_trend.go
+	"📈 Trend: %s":                    "📈 走势: %s",
+	"Loading trend data...\n":        "正在加载走势数据...\n",
+	"Error loading data: %v":         "加载数据失败: %v",
+	"Price: $%.2f • Change: %.2f%%":  "价格: $%.2f • 涨跌: %.2f%%",
+	"Close":                          "收盘",
+	"Press [Esc] to return":          "按 [Esc] 返回",
+	"Not enough data for indicators": "数据不足，无法计算指标",
+}
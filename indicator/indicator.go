@@ -0,0 +1,186 @@
+// Package indicator computes technical indicators over stock.Candle series.
+//
+// Batch functions return a slice aligned with the input bars. Bars that are
+// still warming up, or that are gaps in the data (missing or non-positive
+// prices, which Yahoo reports for halted days), yield NaN and do not advance
+// the indicator. Every batch function is built on a streaming type so values
+// can also be updated tick by tick.
+package indicator
+
+import (
+	"math"
+	"time"
+
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// isGap reports whether a price is missing
+func isGap(v float64) bool {
+	return math.IsNaN(v) || v <= 0
+}
+
+// at returns s[i] or NaN if the slice is too short
+func at(s []float64, i int) float64 {
+	if i < len(s) {
+		return s[i]
+	}
+	return math.NaN()
+}
+
+// Closes returns the close prices of c with gaps as NaN
+func Closes(c *stock.Candle) []float64 {
+	out := make([]float64, len(c.C))
+	for i, v := range c.C {
+		if isGap(v) {
+			out[i] = math.NaN()
+		} else {
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// Last returns the last non-NaN value of s and whether one exists
+func Last(s []float64) (float64, bool) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if !math.IsNaN(s[i]) {
+			return s[i], true
+		}
+	}
+	return math.NaN(), false
+}
+
+// LastTwo returns the last two non-NaN values of s (previous, current)
+func LastTwo(s []float64) (prev, cur float64, ok bool) {
+	found := 0
+	for i := len(s) - 1; i >= 0 && found < 2; i-- {
+		if math.IsNaN(s[i]) {
+			continue
+		}
+		if found == 0 {
+			cur = s[i]
+		} else {
+			prev = s[i]
+		}
+		found++
+	}
+	return prev, cur, found == 2
+}
+
+// series feeds values through update, mapping gaps to NaN
+func series(values []float64, update func(float64) float64) []float64 {
+	out := make([]float64, len(values))
+	for i, v := range values {
+		if isGap(v) {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = update(v)
+	}
+	return out
+}
+
+// SMA computes the simple moving average over period values
+func SMA(values []float64, period int) []float64 {
+	return series(values, NewSMAStream(period).Update)
+}
+
+// EMA computes the exponential moving average, seeded with the SMA of the first period values
+func EMA(values []float64, period int) []float64 {
+	return series(values, NewEMAStream(period).Update)
+}
+
+// RSI computes Wilder's relative strength index
+func RSI(values []float64, period int) []float64 {
+	return series(values, NewRSIStream(period).Update)
+}
+
+// MACDResult holds the MACD line, signal line and histogram
+type MACDResult struct {
+	MACD      []float64
+	Signal    []float64
+	Histogram []float64
+}
+
+// MACD computes the moving average convergence divergence (typically 12, 26, 9)
+func MACD(values []float64, fast, slow, signal int) MACDResult {
+	s := NewMACDStream(fast, slow, signal)
+	res := MACDResult{
+		MACD:      make([]float64, len(values)),
+		Signal:    make([]float64, len(values)),
+		Histogram: make([]float64, len(values)),
+	}
+	for i, v := range values {
+		if isGap(v) {
+			res.MACD[i], res.Signal[i], res.Histogram[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		res.MACD[i], res.Signal[i], res.Histogram[i] = s.Update(v)
+	}
+	return res
+}
+
+// BollingerResult holds the Bollinger Bands
+type BollingerResult struct {
+	Upper  []float64
+	Middle []float64
+	Lower  []float64
+}
+
+// Bollinger computes Bollinger Bands: SMA(period) ± k population standard deviations
+func Bollinger(values []float64, period int, k float64) BollingerResult {
+	s := NewBollingerStream(period, k)
+	res := BollingerResult{
+		Upper:  make([]float64, len(values)),
+		Middle: make([]float64, len(values)),
+		Lower:  make([]float64, len(values)),
+	}
+	for i, v := range values {
+		if isGap(v) {
+			res.Upper[i], res.Middle[i], res.Lower[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		res.Upper[i], res.Middle[i], res.Lower[i] = s.Update(v)
+	}
+	return res
+}
+
+// ATR computes Wilder's average true range
+func ATR(c *stock.Candle, period int) []float64 {
+	s := NewATRStream(period)
+	out := make([]float64, len(c.C))
+	for i := range c.C {
+		h, l, cl := at(c.H, i), at(c.L, i), at(c.C, i)
+		if isGap(h) || isGap(l) || isGap(cl) {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = s.Update(h, l, cl)
+	}
+	return out
+}
+
+// VWAP computes the volume weighted average price using the typical price (H+L+C)/3.
+// The average resets whenever the UTC date of the bar changes, so intraday
+// candles get a per-session VWAP and daily candles a per-bar one.
+func VWAP(c *stock.Candle) []float64 {
+	s := NewVWAPStream()
+	out := make([]float64, len(c.C))
+	var day string
+	for i := range c.C {
+		if i < len(c.T) {
+			d := time.Unix(c.T[i], 0).UTC().Format("2006-01-02")
+			if d != day {
+				s.Reset()
+				day = d
+			}
+		}
+		h, l, cl, v := at(c.H, i), at(c.L, i), at(c.C, i), at(c.V, i)
+		if isGap(h) || isGap(l) || isGap(cl) || math.IsNaN(v) {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = s.Update(h, l, cl, v)
+	}
+	return out
+}
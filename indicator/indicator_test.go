@@ -0,0 +1,316 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// Closes of the StockCharts "Moving Averages" worked example (cs-movavg.xls)
+var movingAverageCloses = []float64{
+	22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29,
+	22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63,
+	23.82, 23.87, 23.65, 23.19, 23.10, 23.33, 22.68, 23.10, 22.40, 22.17,
+}
+
+// Closes of the StockCharts "Relative Strength Index" worked example (cs-rsi.xls)
+var rsiCloses = []float64{
+	44.3389, 44.0902, 44.1497, 43.6124, 44.3278, 44.8264, 45.0955, 45.4245, 45.8433, 46.0826,
+	45.8931, 46.0328, 45.6140, 46.2820, 46.2820, 46.0028, 46.0328, 46.4116, 46.2222, 45.6439,
+	46.2122, 46.2521, 45.7137, 46.4515, 45.7835, 45.3548, 44.0288, 44.1783, 44.2181, 44.5672,
+	43.4205, 42.6628, 43.1314,
+}
+
+// Closes of the StockCharts "Bollinger Bands" worked example (cs-bollinger.xls)
+var bollingerCloses = []float64{
+	86.16, 89.09, 88.78, 90.32, 89.07, 91.15, 89.44, 89.18, 86.93, 87.68,
+	86.96, 89.43, 89.32, 88.72, 87.45, 87.26, 89.50, 87.90, 89.13, 90.70,
+	92.90, 92.98, 91.80, 92.66, 92.68, 92.30, 92.77, 92.54, 92.95, 93.20,
+	91.07, 89.83, 89.74, 90.40, 90.74, 88.02, 88.09, 88.84, 90.78, 90.54,
+	91.39, 90.65,
+}
+
+// assertSeries checks got against want, rounded to two decimals as the worked
+// examples are. NaN in want means the indicator is still warming up.
+func assertSeries(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d values, want %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			if !math.IsNaN(got[i]) {
+				t.Errorf("%s[%d] = %.4f, want NaN", name, i, got[i])
+			}
+			continue
+		}
+		if math.IsNaN(got[i]) || math.Abs(got[i]-want[i]) > 0.0051 {
+			t.Errorf("%s[%d] = %.4f, want %.2f", name, i, got[i], want[i])
+		}
+	}
+}
+
+// warmup returns n NaNs followed by values
+func warmup(n int, values ...float64) []float64 {
+	out := make([]float64, n, n+len(values))
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return append(out, values...)
+}
+
+// sameValue reports whether a and b are equal or both NaN
+func sameValue(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSMA(t *testing.T) {
+	want := warmup(9,
+		22.22, 22.21, 22.23, 22.26, 22.30, 22.42, 22.61, 22.77, 22.91, 23.08, 23.21,
+		23.38, 23.52, 23.65, 23.71, 23.68, 23.61, 23.50, 23.43, 23.28, 23.13)
+	assertSeries(t, "SMA(10)", SMA(movingAverageCloses, 10), want)
+}
+
+func TestEMA(t *testing.T) {
+	want := warmup(9,
+		22.22, 22.21, 22.24, 22.27, 22.33, 22.52, 22.80, 22.97, 23.13, 23.28, 23.34,
+		23.43, 23.51, 23.53, 23.47, 23.40, 23.39, 23.26, 23.23, 23.08, 22.92)
+	assertSeries(t, "EMA(10)", EMA(movingAverageCloses, 10), want)
+}
+
+func TestRSI(t *testing.T) {
+	want := warmup(14,
+		70.53, 66.32, 66.55, 69.41, 66.36, 57.97, 62.93, 63.26, 56.06, 62.38,
+		54.71, 50.42, 39.99, 41.46, 41.87, 45.46, 37.30, 33.08, 37.77)
+	assertSeries(t, "RSI(14)", RSI(rsiCloses, 14), want)
+}
+
+func TestBollinger(t *testing.T) {
+	bb := Bollinger(bollingerCloses, 20, 2)
+	assertSeries(t, "Bollinger middle", bb.Middle, warmup(19,
+		88.71, 89.05, 89.24, 89.39, 89.51, 89.69, 89.75, 89.91, 90.08, 90.38, 90.66,
+		90.86, 90.88, 90.90, 90.99, 91.15, 91.19, 91.12, 91.17, 91.25, 91.24, 91.17, 91.05))
+	assertSeries(t, "Bollinger upper", bb.Upper, warmup(19,
+		91.29, 91.95, 92.61, 92.93, 93.31, 93.73, 93.90, 94.26, 94.56, 94.79, 95.04,
+		94.91, 94.90, 94.89, 94.86, 94.67, 94.55, 94.68, 94.57, 94.53, 94.53, 94.37, 94.15))
+	assertSeries(t, "Bollinger lower", bb.Lower, warmup(19,
+		86.13, 86.14, 85.87, 85.85, 85.70, 85.65, 85.59, 85.56, 85.60, 85.98, 86.27,
+		86.82, 86.86, 86.91, 87.12, 87.63, 87.83, 87.56, 87.76, 87.97, 87.95, 87.96, 87.95))
+}
+
+func TestMACD(t *testing.T) {
+	// The MACD line is EMA(12) - EMA(26) and the signal line the EMA(9) of the
+	// MACD line from its first value on, both built on the EMA checked above
+	values := append(append(append([]float64{}, bollingerCloses...), movingAverageCloses...), rsiCloses...)
+	res := MACD(values, 12, 26, 9)
+	fast, slow := EMA(values, 12), EMA(values, 26)
+
+	macd := make([]float64, len(values))
+	for i := range values {
+		macd[i] = fast[i] - slow[i]
+	}
+	// The MACD line goes negative, which the batch EMA would take for gaps
+	signal := warmup(25)
+	ema := NewEMAStream(9)
+	for _, m := range macd[25:] {
+		signal = append(signal, ema.Update(m))
+	}
+
+	for i := range values {
+		if !sameValue(res.MACD[i], macd[i]) {
+			t.Errorf("MACD[%d] = %.4f, want %.4f", i, res.MACD[i], macd[i])
+		}
+		if !sameValue(res.Signal[i], signal[i]) {
+			t.Errorf("Signal[%d] = %.4f, want %.4f", i, res.Signal[i], signal[i])
+		}
+		if !sameValue(res.Histogram[i], macd[i]-signal[i]) {
+			t.Errorf("Histogram[%d] = %.4f, want %.4f", i, res.Histogram[i], macd[i]-signal[i])
+		}
+	}
+	if math.IsNaN(res.Signal[33]) || !math.IsNaN(res.Signal[32]) {
+		t.Errorf("signal line should start at bar 33 (26 + 9 - 2)")
+	}
+}
+
+// atrCandle has bars whose true range comes from each of its three terms
+var atrCandle = &stock.Candle{
+	H: []float64{10, 11, 12, 11.5, 13, 12},
+	L: []float64{9, 10, 10.5, 10, 12.5, 11},
+	C: []float64{9.5, 10.5, 11, 10.2, 12.8, 11.2},
+}
+
+func TestATR(t *testing.T) {
+	// True ranges: 1 (first bar, high - low), 1.5 (high - prev close 9.5),
+	// 1.5 (high - low), 1.5 (high - low), 2.8 (high - prev close 10.2),
+	// 1.8 (prev close 12.8 - low). Seeded with the simple average of the
+	// first three, then smoothed as (prev × 2 + TR) / 3.
+	seed := (1 + 1.5 + 1.5) / 3.0
+	a4 := (seed*2 + 1.5) / 3
+	a5 := (a4*2 + 2.8) / 3
+	a6 := (a5*2 + 1.8) / 3
+	want := warmup(2, seed, a4, a5, a6)
+
+	got := ATR(atrCandle, 3)
+	for i := range want {
+		if !sameValue(got[i], want[i]) {
+			t.Errorf("ATR(3)[%d] = %.6f, want %.6f", i, got[i], want[i])
+		}
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	day1 := time.Date(2024, 6, 3, 14, 0, 0, 0, time.UTC).Unix()
+	day2 := time.Date(2024, 6, 4, 14, 0, 0, 0, time.UTC).Unix()
+	c := &stock.Candle{
+		T: []int64{day1, day1 + 60, day1 + 120, day2},
+		H: []float64{11, 12, 13, 20},
+		L: []float64{9, 10, 11, 18},
+		C: []float64{10, 11, 12, 19},
+		V: []float64{100, 300, 0, 50},
+	}
+	// Typical prices 10, 11, 12; the third bar has no volume and the fourth
+	// starts a new session
+	want := []float64{10, (10*100 + 11*300) / 400.0, (10*100 + 11*300) / 400.0, 19}
+
+	got := VWAP(c)
+	for i := range want {
+		if !sameValue(got[i], want[i]) {
+			t.Errorf("VWAP[%d] = %.6f, want %.6f", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGapsDoNotAdvance(t *testing.T) {
+	gapped := []float64{22.27, math.NaN(), 22.19, 0, 22.08}
+	got := SMA(gapped, 3)
+	if !math.IsNaN(got[1]) || !math.IsNaN(got[3]) {
+		t.Errorf("gaps should yield NaN, got %v", got)
+	}
+	if want := (22.27 + 22.19 + 22.08) / 3; !sameValue(got[4], want) {
+		t.Errorf("SMA over gaps = %.4f, want %.4f", got[4], want)
+	}
+}
+
+// Each stream must match its batch function bar by bar, and Peek must return
+// what Update would without changing the stream
+
+func TestSMAStream(t *testing.T) {
+	batch := SMA(movingAverageCloses, 10)
+	s := NewSMAStream(10)
+	for i, v := range movingAverageCloses {
+		before := s.Value()
+		peek := s.Peek(v)
+		if !sameValue(s.Value(), before) {
+			t.Fatalf("Peek changed the stream at bar %d", i)
+		}
+		if got := s.Update(v); !sameValue(got, batch[i]) || !sameValue(peek, got) {
+			t.Errorf("bar %d: Update %.4f, Peek %.4f, batch %.4f", i, got, peek, batch[i])
+		}
+	}
+}
+
+func TestEMAStream(t *testing.T) {
+	batch := EMA(movingAverageCloses, 10)
+	s := NewEMAStream(10)
+	for i, v := range movingAverageCloses {
+		before := s.Value()
+		peek := s.Peek(v)
+		if !sameValue(s.Value(), before) {
+			t.Fatalf("Peek changed the stream at bar %d", i)
+		}
+		if got := s.Update(v); !sameValue(got, batch[i]) || !sameValue(peek, got) {
+			t.Errorf("bar %d: Update %.4f, Peek %.4f, batch %.4f", i, got, peek, batch[i])
+		}
+	}
+}
+
+func TestRSIStream(t *testing.T) {
+	batch := RSI(rsiCloses, 14)
+	s := NewRSIStream(14)
+	for i, v := range rsiCloses {
+		before := s.Value()
+		peek := s.Peek(v)
+		if !sameValue(s.Value(), before) {
+			t.Fatalf("Peek changed the stream at bar %d", i)
+		}
+		if got := s.Update(v); !sameValue(got, batch[i]) || !sameValue(peek, got) {
+			t.Errorf("bar %d: Update %.4f, Peek %.4f, batch %.4f", i, got, peek, batch[i])
+		}
+	}
+}
+
+func TestMACDStream(t *testing.T) {
+	values := append(append([]float64{}, bollingerCloses...), movingAverageCloses...)
+	batch := MACD(values, 12, 26, 9)
+	s := NewMACDStream(12, 26, 9)
+	for i, v := range values {
+		bm, bs, _ := s.Value()
+		pm, ps, ph := s.Peek(v)
+		if am, as, _ := s.Value(); !sameValue(am, bm) || !sameValue(as, bs) {
+			t.Fatalf("Peek changed the stream at bar %d", i)
+		}
+		m, sig, h := s.Update(v)
+		if !sameValue(m, batch.MACD[i]) || !sameValue(sig, batch.Signal[i]) || !sameValue(h, batch.Histogram[i]) {
+			t.Errorf("bar %d: Update (%.4f, %.4f, %.4f), batch (%.4f, %.4f, %.4f)",
+				i, m, sig, h, batch.MACD[i], batch.Signal[i], batch.Histogram[i])
+		}
+		if !sameValue(pm, m) || !sameValue(ps, sig) || !sameValue(ph, h) {
+			t.Errorf("bar %d: Peek (%.4f, %.4f, %.4f), Update (%.4f, %.4f, %.4f)", i, pm, ps, ph, m, sig, h)
+		}
+	}
+}
+
+func TestBollingerStream(t *testing.T) {
+	batch := Bollinger(bollingerCloses, 20, 2)
+	s := NewBollingerStream(20, 2)
+	for i, v := range bollingerCloses {
+		pu, pm, pl := s.Peek(v)
+		u, m, l := s.Update(v)
+		if !sameValue(u, batch.Upper[i]) || !sameValue(m, batch.Middle[i]) || !sameValue(l, batch.Lower[i]) {
+			t.Errorf("bar %d: Update (%.4f, %.4f, %.4f), batch (%.4f, %.4f, %.4f)",
+				i, u, m, l, batch.Upper[i], batch.Middle[i], batch.Lower[i])
+		}
+		if !sameValue(pu, u) || !sameValue(pm, m) || !sameValue(pl, l) {
+			t.Errorf("bar %d: Peek (%.4f, %.4f, %.4f), Update (%.4f, %.4f, %.4f)", i, pu, pm, pl, u, m, l)
+		}
+	}
+}
+
+func TestATRStream(t *testing.T) {
+	batch := ATR(atrCandle, 3)
+	s := NewATRStream(3)
+	for i := range atrCandle.C {
+		got := s.Update(atrCandle.H[i], atrCandle.L[i], atrCandle.C[i])
+		if !sameValue(got, batch[i]) || !sameValue(s.Value(), got) {
+			t.Errorf("bar %d: Update %.4f, Value %.4f, batch %.4f", i, got, s.Value(), batch[i])
+		}
+	}
+}
+
+func TestVWAPStream(t *testing.T) {
+	c := &stock.Candle{
+		H: []float64{11, 12, 13},
+		L: []float64{9, 10, 11},
+		C: []float64{10, 11, 12},
+		V: []float64{100, 300, 200},
+	}
+	batch := VWAP(c)
+	s := NewVWAPStream()
+	if !math.IsNaN(s.Value()) {
+		t.Errorf("VWAP without volume = %.4f, want NaN", s.Value())
+	}
+	for i := range c.C {
+		if got := s.Update(c.H[i], c.L[i], c.C[i], c.V[i]); !sameValue(got, batch[i]) {
+			t.Errorf("bar %d: Update %.4f, batch %.4f", i, got, batch[i])
+		}
+	}
+	s.Reset()
+	if !math.IsNaN(s.Value()) {
+		t.Errorf("VWAP after Reset = %.4f, want NaN", s.Value())
+	}
+}
@@ -0,0 +1,323 @@
+package indicator
+
+import "math"
+
+// SMAStream is a streaming simple moving average
+type SMAStream struct {
+	period int
+	window []float64
+	sum    float64
+}
+
+// NewSMAStream creates a streaming SMA over period values
+func NewSMAStream(period int) *SMAStream {
+	if period < 1 {
+		period = 1
+	}
+	return &SMAStream{period: period}
+}
+
+// Update adds a value and returns the average, or NaN while warming up
+func (s *SMAStream) Update(v float64) float64 {
+	s.window = append(s.window, v)
+	s.sum += v
+	if len(s.window) > s.period {
+		s.sum -= s.window[0]
+		s.window = s.window[1:]
+	}
+	return s.Value()
+}
+
+// Peek returns the average as if v were the next value, without adding it
+func (s *SMAStream) Peek(v float64) float64 {
+	n := len(s.window) + 1
+	sum := s.sum + v
+	if n > s.period {
+		sum -= s.window[0]
+		n = s.period
+	}
+	if n < s.period {
+		return math.NaN()
+	}
+	return sum / float64(s.period)
+}
+
+// Value returns the current average, or NaN while warming up
+func (s *SMAStream) Value() float64 {
+	if len(s.window) < s.period {
+		return math.NaN()
+	}
+	return s.sum / float64(s.period)
+}
+
+// EMAStream is a streaming exponential moving average seeded with an SMA
+type EMAStream struct {
+	period int
+	alpha  float64
+	seed   *SMAStream
+	value  float64
+	ready  bool
+}
+
+// NewEMAStream creates a streaming EMA over period values
+func NewEMAStream(period int) *EMAStream {
+	if period < 1 {
+		period = 1
+	}
+	return &EMAStream{
+		period: period,
+		alpha:  2 / float64(period+1),
+		seed:   NewSMAStream(period),
+		value:  math.NaN(),
+	}
+}
+
+// Update adds a value and returns the EMA, or NaN while warming up
+func (s *EMAStream) Update(v float64) float64 {
+	if !s.ready {
+		s.value = s.seed.Update(v)
+		s.ready = !math.IsNaN(s.value)
+		return s.value
+	}
+	s.value += s.alpha * (v - s.value)
+	return s.value
+}
+
+// Peek returns the EMA as if v were the next value, without adding it
+func (s *EMAStream) Peek(v float64) float64 {
+	if !s.ready {
+		return s.seed.Peek(v)
+	}
+	return s.value + s.alpha*(v-s.value)
+}
+
+// Value returns the current EMA, or NaN while warming up
+func (s *EMAStream) Value() float64 {
+	return s.value
+}
+
+// RSIStream is a streaming Wilder RSI
+type RSIStream struct {
+	period  int
+	prev    float64
+	count   int // Number of price changes seen
+	avgGain float64
+	avgLoss float64
+}
+
+// NewRSIStream creates a streaming RSI over period changes
+func NewRSIStream(period int) *RSIStream {
+	if period < 1 {
+		period = 1
+	}
+	return &RSIStream{period: period, prev: math.NaN()}
+}
+
+func (s *RSIStream) next(v float64) (avgGain, avgLoss float64, count int) {
+	if math.IsNaN(s.prev) {
+		return 0, 0, 0
+	}
+	change := v - s.prev
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+	count = s.count + 1
+	p := float64(s.period)
+	if count <= s.period {
+		// Seed with a simple average of the first period changes
+		return s.avgGain + gain/p, s.avgLoss + loss/p, count
+	}
+	return (s.avgGain*(p-1) + gain) / p, (s.avgLoss*(p-1) + loss) / p, count
+}
+
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+avgGain/avgLoss)
+}
+
+// Update adds a value and returns the RSI, or NaN while warming up
+func (s *RSIStream) Update(v float64) float64 {
+	s.avgGain, s.avgLoss, s.count = s.next(v)
+	s.prev = v
+	return s.Value()
+}
+
+// Peek returns the RSI as if v were the next value, without adding it
+func (s *RSIStream) Peek(v float64) float64 {
+	gain, loss, count := s.next(v)
+	if count < s.period {
+		return math.NaN()
+	}
+	return rsiValue(gain, loss)
+}
+
+// Value returns the current RSI, or NaN while warming up
+func (s *RSIStream) Value() float64 {
+	if s.count < s.period {
+		return math.NaN()
+	}
+	return rsiValue(s.avgGain, s.avgLoss)
+}
+
+// MACDStream is a streaming MACD
+type MACDStream struct {
+	fast   *EMAStream
+	slow   *EMAStream
+	signal *EMAStream
+	macd   float64
+	sig    float64
+}
+
+// NewMACDStream creates a streaming MACD
+func NewMACDStream(fast, slow, signal int) *MACDStream {
+	return &MACDStream{
+		fast:   NewEMAStream(fast),
+		slow:   NewEMAStream(slow),
+		signal: NewEMAStream(signal),
+		macd:   math.NaN(),
+		sig:    math.NaN(),
+	}
+}
+
+// Update adds a value and returns the MACD line, signal line and histogram
+func (s *MACDStream) Update(v float64) (macd, signal, hist float64) {
+	f, sl := s.fast.Update(v), s.slow.Update(v)
+	s.macd = f - sl
+	if math.IsNaN(s.macd) {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	s.sig = s.signal.Update(s.macd)
+	return s.macd, s.sig, s.macd - s.sig
+}
+
+// Peek returns the MACD values as if v were the next value, without adding it
+func (s *MACDStream) Peek(v float64) (macd, signal, hist float64) {
+	macd = s.fast.Peek(v) - s.slow.Peek(v)
+	if math.IsNaN(macd) {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	signal = s.signal.Peek(macd)
+	return macd, signal, macd - signal
+}
+
+// Value returns the current MACD line, signal line and histogram
+func (s *MACDStream) Value() (macd, signal, hist float64) {
+	return s.macd, s.sig, s.macd - s.sig
+}
+
+// BollingerStream is a streaming Bollinger Bands calculator
+type BollingerStream struct {
+	sma    *SMAStream
+	k      float64
+	period int
+}
+
+// NewBollingerStream creates streaming Bollinger Bands
+func NewBollingerStream(period int, k float64) *BollingerStream {
+	sma := NewSMAStream(period)
+	return &BollingerStream{sma: sma, k: k, period: sma.period}
+}
+
+func (s *BollingerStream) bands(window []float64, mean float64) (upper, middle, lower float64) {
+	if math.IsNaN(mean) {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	var sq float64
+	for _, w := range window {
+		sq += (w - mean) * (w - mean)
+	}
+	sd := math.Sqrt(sq / float64(len(window)))
+	return mean + s.k*sd, mean, mean - s.k*sd
+}
+
+// Update adds a value and returns the upper, middle and lower bands
+func (s *BollingerStream) Update(v float64) (upper, middle, lower float64) {
+	mean := s.sma.Update(v)
+	return s.bands(s.sma.window, mean)
+}
+
+// Peek returns the bands as if v were the next value, without adding it
+func (s *BollingerStream) Peek(v float64) (upper, middle, lower float64) {
+	window := append(append([]float64{}, s.sma.window...), v)
+	if len(window) > s.period {
+		window = window[1:]
+	}
+	return s.bands(window, s.sma.Peek(v))
+}
+
+// ATRStream is a streaming Wilder average true range
+type ATRStream struct {
+	period    int
+	prevClose float64
+	count     int
+	seedSum   float64
+	value     float64
+}
+
+// NewATRStream creates a streaming ATR
+func NewATRStream(period int) *ATRStream {
+	if period < 1 {
+		period = 1
+	}
+	return &ATRStream{period: period, prevClose: math.NaN(), value: math.NaN()}
+}
+
+// Update adds a bar and returns the ATR, or NaN while warming up
+func (s *ATRStream) Update(high, low, close float64) float64 {
+	tr := high - low
+	if !math.IsNaN(s.prevClose) {
+		tr = math.Max(tr, math.Max(math.Abs(high-s.prevClose), math.Abs(low-s.prevClose)))
+	}
+	s.prevClose = close
+	s.count++
+
+	p := float64(s.period)
+	switch {
+	case s.count < s.period:
+		s.seedSum += tr
+	case s.count == s.period:
+		s.value = (s.seedSum + tr) / p
+	default:
+		s.value = (s.value*(p-1) + tr) / p
+	}
+	return s.value
+}
+
+// Value returns the current ATR, or NaN while warming up
+func (s *ATRStream) Value() float64 {
+	return s.value
+}
+
+// VWAPStream is a streaming volume weighted average price
+type VWAPStream struct {
+	pv  float64
+	vol float64
+}
+
+// NewVWAPStream creates a streaming VWAP
+func NewVWAPStream() *VWAPStream {
+	return &VWAPStream{}
+}
+
+// Reset starts a new session
+func (s *VWAPStream) Reset() {
+	s.pv, s.vol = 0, 0
+}
+
+// Update adds a bar and returns the VWAP, or NaN if no volume was traded yet
+func (s *VWAPStream) Update(high, low, close, volume float64) float64 {
+	s.pv += (high + low + close) / 3 * volume
+	s.vol += volume
+	return s.Value()
+}
+
+// Value returns the current VWAP, or NaN if no volume was traded yet
+func (s *VWAPStream) Value() float64 {
+	if s.vol == 0 {
+		return math.NaN()
+	}
+	return s.pv / s.vol
+}
@@ -0,0 +1,36 @@
+// Package filelock provides advisory, inter-process file locks so that
+// several stock-ping processes (e.g. watch and dashboard) can share state files.
+package filelock
+
+import (
+	"fmt"
+	"os"
+)
+
+// Lock is a held exclusive lock
+type Lock struct {
+	f    *os.File
+	path string
+}
+
+// Acquire blocks until an exclusive lock on path is held, creating the file if needed
+func Acquire(path string) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := lock(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return &Lock{f: f, path: path}, nil
+}
+
+// Release releases the lock
+func (l *Lock) Release() error {
+	err := unlock(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/congregalis/stock-ping/cmd"
+	"github.com/congregalis/stock-ping/i18n"
 )
 
 const version = "1.0.0"
@@ -28,34 +29,46 @@ func main() {
 		cmd.RunHolding(os.Args[2:])
 	case "config":
 		cmd.RunConfig(os.Args[2:])
+	case "history":
+		cmd.RunHistory(os.Args[2:])
+	case "alerts":
+		cmd.RunAlerts(os.Args[2:])
+	case "backtest":
+		cmd.RunBacktest(os.Args[2:])
+	case "rule":
+		cmd.RunRule(os.Args[2:])
 	case "version", "-v", "--version":
 		fmt.Printf("stock-ping version %s\n", version)
 	case "help", "-h", "--help":
 		printUsage()
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
+		i18n.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
 		printUsage()
 		os.Exit(1)
 	}
 }
 
 func printUsage() {
-	fmt.Println("stock-ping - A simple stock monitoring CLI tool")
+	i18n.Println("stock-ping - A simple stock monitoring CLI tool")
 	fmt.Println()
-	fmt.Println("Usage:")
+	i18n.Println("Usage:")
 	fmt.Println("  stock-ping <command> [options]")
 	fmt.Println()
-	fmt.Println("Commands:")
-	fmt.Println("  add [options]    Quickly add a new monitoring rule")
-	fmt.Println("  once <SYMBOL>    Query current price for a single stock")
-	fmt.Println("  watch            Continuously monitor stocks (text mode)")
-	fmt.Println("  dashboard        Interactive TUI dashboard with hot-reload")
-	fmt.Println("  holding          Manage portfolio holdings (add/list/remove)")
-	fmt.Println("  config           Manage monitoring rules (add/list/remove)")
-	fmt.Println("  version          Show version information")
-	fmt.Println("  help             Show this help message")
+	i18n.Println("Commands:")
+	i18n.Println("  add [options]    Quickly add a new monitoring rule")
+	i18n.Println("  once <SYMBOL>    Query current price for a single stock")
+	i18n.Println("  watch            Continuously monitor stocks (text mode)")
+	i18n.Println("  dashboard        Interactive TUI dashboard with hot-reload")
+	i18n.Println("  holding          Manage portfolio holdings (add/list/remove)")
+	i18n.Println("  config           Manage monitoring rules (add/list/remove)")
+	i18n.Println("  history <SYMBOL> Show locally recorded quote history")
+	i18n.Println("  alerts           Show and export the history of fired alerts")
+	i18n.Println("  backtest         Replay historical candles through a symbol's rules")
+	i18n.Println("  rule test        Dry-run a symbol's rules against a made-up quote")
+	i18n.Println("  version          Show version information")
+	i18n.Println("  help             Show this help message")
 	fmt.Println()
-	fmt.Println("Examples:")
+	i18n.Println("Examples:")
 	fmt.Println("  stock-ping add --symbol AAPL --price-above 200")
 	fmt.Println("  stock-ping once AAPL")
 	fmt.Println("  stock-ping config add --symbol AAPL --price-above 200")
@@ -63,8 +76,9 @@ func printUsage() {
 	fmt.Println("  stock-ping watch")
 	fmt.Println("  stock-ping dashboard")
 	fmt.Println()
-	fmt.Println("Configuration:")
-	fmt.Println("  Config file: ~/.stock-ping.yaml")
+	i18n.Println("Configuration:")
+	i18n.Println("  Config file: ~/.stock-ping.yaml")
+	i18n.Println("  Data dir:    ~/.local/share/stock-ping")
 	fmt.Println()
-	fmt.Println("For more information, visit: https://github.com/congregalis/stock-ping")
+	i18n.Println("For more information, visit: https://github.com/congregalis/stock-ping")
 }
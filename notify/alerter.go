@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/rule"
+	"github.com/congregalis/stock-ping/stock"
+	"github.com/congregalis/stock-ping/store"
+)
+
+// Delivery is what happened to the alerts handed to an Alerter
+type Delivery struct {
+	Alerts    int   // Alerts handled, 0 if nothing was due
+	Batched   bool  // Held back for the digest
+	Pushed    bool  // A notification was sent, see SendErr
+	SendErr   error // Error sending the notification
+	RecordErr error // Error recording the alerts in the alert history
+	StateErr  error // Error saving the alert state
+}
+
+// Alerter delivers the alerts of watch and the dashboard. It pushes new
+// alerts, collects them into a digest if one is configured, sends the alerts
+// held during quiet hours once they are over, and records every alert in the
+// alert history.
+type Alerter struct {
+	notifier *Notifier
+	log      *store.AlertLog // nil if alerts are not recorded
+	batch    rule.Batch
+}
+
+// NewAlerter creates an alerter. log may be nil to not record alerts.
+func NewAlerter(notifier *Notifier, log *store.AlertLog) *Alerter {
+	return &Alerter{notifier: notifier, log: log}
+}
+
+// Send notifies about the new conditions of subject raised on quote, or adds
+// them to the digest unless they are severe enough to go out right away
+func (a *Alerter) Send(cfg *config.Config, subject string, quote *stock.Quote, conditions []rule.Condition, format func() (string, string)) Delivery {
+	if len(conditions) == 0 {
+		return Delivery{}
+	}
+	alerts := rule.QueueAlerts(subject, quote, conditions, time.Now())
+	d := Delivery{Alerts: len(alerts)}
+	if !a.notifier.IsConfigured() {
+		d.RecordErr = a.record(alerts, "", nil)
+		return d
+	}
+	severity := rule.HighestSeverity(conditions)
+	if cfg.Digest.Batches(severity) {
+		a.batch.Add(alerts)
+		d.Batched = true
+		return d
+	}
+	title, body := format()
+	d.Pushed = true
+	d.SendErr = a.notifier.SendAlert(title, body, severity, cfg.Bark)
+	d.RecordErr = a.record(alerts, "", d.SendErr)
+	return d
+}
+
+// Flush sends the digest of collected alerts once its window has passed, or
+// right away if force is set, e.g. on shutdown
+func (a *Alerter) Flush(cfg *config.Config, force bool) Delivery {
+	var window time.Duration
+	if cfg.Digest != nil && !force {
+		window = time.Duration(cfg.Digest.Window)
+	}
+	batch := a.batch.Flush(time.Now(), window)
+	if len(batch) == 0 {
+		return Delivery{}
+	}
+	title, body := rule.FormatBatch(batch)
+	d := Delivery{Alerts: len(batch), Pushed: true}
+	d.SendErr = a.notifier.SendAlert(title, body, rule.DigestSeverity(batch), cfg.Bark)
+	d.RecordErr = a.record(batch, store.ViaDigest, d.SendErr)
+	return d
+}
+
+// FlushQuietHours sends the alerts tracker queued during quiet hours once
+// they are over. If the notification fails they stay queued for the next call.
+func (a *Alerter) FlushQuietHours(cfg *config.Config, tracker *rule.Tracker) Delivery {
+	var d Delivery
+	digest, err := tracker.FlushDigest(time.Now())
+	d.StateErr = err
+	if len(digest) == 0 {
+		return d
+	}
+	d.Alerts = len(digest)
+	if a.notifier.IsConfigured() {
+		title, body := rule.FormatDigest(digest)
+		d.Pushed = true
+		if d.SendErr = a.notifier.SendAlert(title, body, rule.DigestSeverity(digest), cfg.Bark); d.SendErr != nil {
+			if err := tracker.RequeueDigest(digest); err != nil {
+				d.StateErr = err
+			}
+			return d
+		}
+	}
+	d.RecordErr = a.record(digest, store.ViaQuietHours, nil)
+	return d
+}
+
+// record adds alerts delivered with the given result to the alert history
+func (a *Alerter) record(alerts []rule.QueuedAlert, via string, sendErr error) error {
+	if a.log == nil {
+		return nil
+	}
+	return a.log.Append(store.NewAlertRecords(alerts, via, a.notifier.Channels(), sendErr))
+}
@@ -5,8 +5,20 @@ import (
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/congregalis/stock-ping/config"
+)
+
+// Bark interruption levels
+const (
+	LevelActive        = "active"        // Default: shown immediately
+	LevelTimeSensitive = "timeSensitive" // Shown even during Focus
+	LevelCritical      = "critical"      // Plays a sound even when muted
 )
 
+// Group is the notification group all stock-ping alerts are stacked under
+const Group = "stock-ping"
+
 // Notifier sends push notifications via Bark
 type Notifier struct {
 	serverURL  string
@@ -14,6 +26,13 @@ type Notifier struct {
 	httpClient *http.Client
 }
 
+// Options control how a notification is delivered
+type Options struct {
+	Group string // Notifications of the same group are stacked together
+	Level string // Interruption level, one of the Level* constants (default active)
+	Sound string // Name of a Bark sound, e.g. alarm (default the device's)
+}
+
 // NewNotifier creates a new Bark notifier
 func NewNotifier(serverURL, key string) *Notifier {
 	if serverURL == "" {
@@ -28,43 +47,64 @@ func NewNotifier(serverURL, key string) *Notifier {
 	}
 }
 
-// Send sends a push notification with title and body
-func (n *Notifier) Send(title, body string) error {
-	if n.key == "" {
-		return fmt.Errorf("bark key is not configured")
-	}
-
-	// URL encode the title and body
-	encodedTitle := url.PathEscape(title)
-	encodedBody := url.PathEscape(body)
-
-	// Build the URL: https://api.day.app/{key}/{title}/{body}
-	notifyURL := fmt.Sprintf("%s/%s/%s/%s", n.serverURL, n.key, encodedTitle, encodedBody)
-
-	resp, err := n.httpClient.Get(notifyURL)
-	if err != nil {
-		return fmt.Errorf("failed to send notification: %w", err)
+// AlertOptions returns the delivery options for an alert of the given severity:
+// warnings are time sensitive and critical alerts ring even when the phone is
+// muted. Sounds configured per severity override the default ones.
+func AlertOptions(severity config.Severity, bark config.BarkConfig) Options {
+	opts := Options{Group: Group}
+	switch severity {
+	case config.SeverityWarning:
+		opts.Level = LevelTimeSensitive
+	case config.SeverityCritical:
+		opts.Level = LevelCritical
+		opts.Sound = "alarm"
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bark returned status %d", resp.StatusCode)
+	if sound, ok := bark.Sounds[severity]; ok {
+		opts.Sound = sound
 	}
+	return opts
+}
 
-	return nil
+// Send sends a push notification with title and body
+func (n *Notifier) Send(title, body string) error {
+	return n.SendWithOptions(title, body, Options{})
 }
 
 // SendWithGroup sends a notification with a group name
 func (n *Notifier) SendWithGroup(title, body, group string) error {
+	return n.SendWithOptions(title, body, Options{Group: group})
+}
+
+// SendAlert sends an alert with the delivery options of its severity
+func (n *Notifier) SendAlert(title, body string, severity config.Severity, bark config.BarkConfig) error {
+	return n.SendWithOptions(title, body, AlertOptions(severity, bark))
+}
+
+// SendWithOptions sends a notification with the given delivery options
+func (n *Notifier) SendWithOptions(title, body string, opts Options) error {
 	if n.key == "" {
 		return fmt.Errorf("bark key is not configured")
 	}
 
+	// URL encode the title and body
 	encodedTitle := url.PathEscape(title)
 	encodedBody := url.PathEscape(body)
 
-	notifyURL := fmt.Sprintf("%s/%s/%s/%s?group=%s",
-		n.serverURL, n.key, encodedTitle, encodedBody, url.QueryEscape(group))
+	// Build the URL: https://api.day.app/{key}/{title}/{body}?group=...
+	notifyURL := fmt.Sprintf("%s/%s/%s/%s", n.serverURL, n.key, encodedTitle, encodedBody)
+	params := url.Values{}
+	if opts.Group != "" {
+		params.Set("group", opts.Group)
+	}
+	if opts.Level != "" {
+		params.Set("level", opts.Level)
+	}
+	if opts.Sound != "" {
+		params.Set("sound", opts.Sound)
+	}
+	if len(params) > 0 {
+		notifyURL += "?" + params.Encode()
+	}
 
 	resp, err := n.httpClient.Get(notifyURL)
 	if err != nil {
@@ -79,6 +119,15 @@ func (n *Notifier) SendWithGroup(title, body, group string) error {
 	return nil
 }
 
+// Channels returns the names of the channels alerts are sent to, none if the
+// notifier is not configured
+func (n *Notifier) Channels() []string {
+	if !n.IsConfigured() {
+		return nil
+	}
+	return []string{"bark"}
+}
+
 // IsConfigured returns true if the notifier is properly configured
 func (n *Notifier) IsConfigured() bool {
 	return n.key != ""
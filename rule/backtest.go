@@ -0,0 +1,252 @@
+package rule
+
+import (
+	"slices"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// ForwardDays are the horizons, in trading days, of backtest forward returns
+var ForwardDays = []int{1, 5, 20}
+
+// BacktestAlert is an alert raised while replaying history
+type BacktestAlert struct {
+	Time    time.Time       `json:"time"`
+	RuleID  string          `json:"rule_id"`
+	Type    string          `json:"type"`
+	Text    string          `json:"text"`
+	Price   float64         `json:"price"`
+	Returns map[int]float64 `json:"returns"` // Forward return in percent by ForwardDays, if known
+}
+
+// HorizonStats summarizes the forward returns of all alerts over one horizon
+type HorizonStats struct {
+	Days    int     `json:"days"`
+	Count   int     `json:"count"` // Alerts with enough history after them
+	Mean    float64 `json:"mean"`
+	Median  float64 `json:"median"`
+	WinRate float64 `json:"win_rate"` // Percent of returns above zero
+	Best    float64 `json:"best"`
+	Worst   float64 `json:"worst"`
+}
+
+// BacktestReport is the result of replaying a symbol's history through its rules
+type BacktestReport struct {
+	Symbol     string          `json:"symbol"`
+	Resolution string          `json:"resolution"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Bars       int             `json:"bars"`
+	Alerts     []BacktestAlert `json:"alerts"`
+	Stats      []HorizonStats  `json:"stats"`
+}
+
+// Backtest replays the bars of series[resolution] between from and to through
+// the rules, with the same edge triggers and alert policies as live checks.
+// series must hold daily candles under "D", used for indicators and forward
+// returns, and may hold other intraday resolutions needed by crossovers. Each
+// bar is seen as a quote at its close; pair legs are not available.
+func Backtest(e *Evaluator, rules []*config.Rule, h *config.Holding, symbol, market, resolution string, series map[string]*stock.Candle, from, to time.Time) *BacktestReport {
+	report := &BacktestReport{Symbol: symbol, Resolution: resolution, From: from, To: to, Alerts: []BacktestAlert{}}
+	bars, daily := series[resolution], series["D"]
+	if bars == nil || daily == nil {
+		report.Stats = backtestStats(report.Alerts)
+		return report
+	}
+
+	tracker := NewTracker(nil)
+	sessionStart := 0 // Index of the first bar of the current intraday session
+	for i := range bars.C {
+		if i >= len(bars.T) {
+			break
+		}
+		now := time.Unix(bars.T[i], 0)
+		if resolution != "D" && i > 0 && !stock.SessionOpen(market, now).Equal(stock.SessionOpen(market, time.Unix(bars.T[i-1], 0))) {
+			sessionStart = i
+		}
+		if now.Before(from) || now.After(to) {
+			continue
+		}
+		report.Bars++
+
+		quote := replayQuote(bars, i, sessionStart, resolution)
+		quote.Symbol = symbol
+		in := Input{
+			Quote:    quote,
+			Candles:  candlesBefore(daily, now),
+			Holding:  h,
+			Market:   market,
+			Intraday: make(map[string]*stock.Candle),
+		}
+		for res, c := range series {
+			if res != "D" {
+				in.Intraday[res] = candlesUntil(c, now)
+			}
+		}
+
+		for _, r := range rules {
+			if !r.IsEnabled() {
+				continue
+			}
+			if resolution == "D" {
+				if r = dailyRule(r, now); r == nil {
+					continue
+				}
+			}
+			o, _ := tracker.Evaluate(e, r, in, now)
+			for _, c := range o.New {
+				report.Alerts = append(report.Alerts, BacktestAlert{
+					Time:    now,
+					RuleID:  c.RuleID,
+					Type:    c.Type,
+					Text:    c.Text,
+					Price:   quote.CurrentPrice,
+					Returns: forwardReturns(daily, now, quote.CurrentPrice),
+				})
+			}
+		}
+	}
+
+	report.Stats = backtestStats(report.Alerts)
+	return report
+}
+
+// dailyRule returns the rule to evaluate on the daily bar of day, nil if its
+// schedule excludes the day. Daily bars are stamped at midnight UTC, so only
+// the days of the schedule can be judged; its times and first minutes are
+// ignored.
+func dailyRule(r *config.Rule, day time.Time) *config.Rule {
+	if r.Active == nil {
+		return r
+	}
+	days := config.Schedule{Days: r.Active.Days}
+	if date := day.UTC(); !days.Contains(date, date) {
+		return nil
+	}
+	daily := *r
+	daily.Active = nil
+	return &daily
+}
+
+// replayQuote builds the quote seen at the close of bar i. Intraday bars are
+// rolled up from the first bar of their session.
+func replayQuote(c *stock.Candle, i, sessionStart int, resolution string) *stock.Quote {
+	q := &stock.Quote{
+		CurrentPrice: c.C[i],
+		Open:         at(c.O, i),
+		High:         at(c.H, i),
+		Low:          at(c.L, i),
+		Volume:       at(c.V, i),
+		Timestamp:    c.T[i],
+	}
+	if i > 0 {
+		q.PrevClose = c.C[i-1]
+	}
+
+	if resolution != "D" {
+		q.Open = at(c.O, sessionStart)
+		q.PrevClose = 0
+		if sessionStart > 0 {
+			q.PrevClose = c.C[sessionStart-1]
+		}
+		for j := sessionStart; j < i; j++ {
+			q.High = max(q.High, at(c.H, j))
+			if l := at(c.L, j); l > 0 {
+				q.Low = min(q.Low, l)
+			}
+			q.Volume += at(c.V, j)
+		}
+	}
+
+	if q.PrevClose > 0 {
+		q.Change = q.CurrentPrice - q.PrevClose
+		q.PercentChange = q.Change / q.PrevClose * 100
+	}
+	return q
+}
+
+// at returns s[i], or 0 if s is too short
+func at(s []float64, i int) float64 {
+	if i < len(s) {
+		return s[i]
+	}
+	return 0
+}
+
+// sliceCandle returns the first n bars of c
+func sliceCandle(c *stock.Candle, n int) *stock.Candle {
+	cut := func(s []float64) []float64 { return s[:min(n, len(s))] }
+	return &stock.Candle{S: c.S, T: c.T[:min(n, len(c.T))], O: cut(c.O), H: cut(c.H), L: cut(c.L), C: cut(c.C), V: cut(c.V)}
+}
+
+// candlesUntil returns the bars of c that started at or before t
+func candlesUntil(c *stock.Candle, t time.Time) *stock.Candle {
+	n, _ := slices.BinarySearch(c.T, t.Unix()+1)
+	return sliceCandle(c, n)
+}
+
+// candlesBefore returns the daily bars of days before t. Daily bars are
+// stamped in UTC, so days are compared by UTC date as liveCandles does.
+func candlesBefore(c *stock.Candle, t time.Time) *stock.Candle {
+	day := t.UTC().Format(config.DateLayout)
+	n := 0
+	for n < len(c.T) && time.Unix(c.T[n], 0).UTC().Format(config.DateLayout) < day {
+		n++
+	}
+	return sliceCandle(c, n)
+}
+
+// forwardReturns returns the percent change from price to the daily close
+// each of ForwardDays trading days after t
+func forwardReturns(daily *stock.Candle, t time.Time, price float64) map[int]float64 {
+	returns := make(map[int]float64)
+	if price <= 0 {
+		return returns
+	}
+	d := len(candlesBefore(daily, t).C) // Index of t's day, if present
+	if d < len(daily.T) && time.Unix(daily.T[d], 0).UTC().Format(config.DateLayout) != t.UTC().Format(config.DateLayout) {
+		d-- // No bar for t's day, count from the day before
+	}
+	for _, days := range ForwardDays {
+		if i := d + days; i >= 0 && i < len(daily.C) {
+			returns[days] = (daily.C[i] - price) / price * 100
+		}
+	}
+	return returns
+}
+
+// backtestStats summarizes the forward returns of alerts per horizon
+func backtestStats(alerts []BacktestAlert) []HorizonStats {
+	var stats []HorizonStats
+	for _, days := range ForwardDays {
+		s := HorizonStats{Days: days}
+		var returns []float64
+		for _, a := range alerts {
+			if r, ok := a.Returns[days]; ok {
+				returns = append(returns, r)
+			}
+		}
+		s.Count = len(returns)
+		if s.Count > 0 {
+			slices.Sort(returns)
+			s.Best, s.Worst = returns[s.Count-1], returns[0]
+			s.Median = returns[s.Count/2]
+			if s.Count%2 == 0 {
+				s.Median = (returns[s.Count/2-1] + returns[s.Count/2]) / 2
+			}
+			wins := 0
+			for _, r := range returns {
+				s.Mean += r
+				if r > 0 {
+					wins++
+				}
+			}
+			s.Mean /= float64(s.Count)
+			s.WinRate = float64(wins) / float64(s.Count) * 100
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
@@ -0,0 +1,73 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/congregalis/stock-ping/i18n"
+)
+
+// Batch collects new alerts for a digest notification, sent at the end of a
+// refresh cycle or once a window has passed since the first alert
+type Batch struct {
+	alerts []QueuedAlert
+}
+
+// Add adds new alerts to the batch
+func (b *Batch) Add(alerts []QueuedAlert) {
+	b.alerts = append(b.alerts, alerts...)
+}
+
+// Flush returns and clears the collected alerts once window has passed since
+// the first one. A zero window flushes whatever was collected.
+func (b *Batch) Flush(now time.Time, window time.Duration) []QueuedAlert {
+	if len(b.alerts) == 0 || now.Sub(b.alerts[0].At) < window {
+		return nil
+	}
+	alerts := b.alerts
+	b.alerts = nil
+	return alerts
+}
+
+// FormatBatch returns one notification listing the alerts as a compact table,
+// one line per subject with its conditions
+func FormatBatch(alerts []QueuedAlert) (title, body string) {
+	title = i18n.T("🔔 %d new alerts", len(alerts))
+
+	var subjects []string
+	texts := make(map[string][]string)
+	width := 0
+	for _, a := range alerts {
+		if _, ok := texts[a.Subject]; !ok {
+			subjects = append(subjects, a.Subject)
+			width = max(width, len([]rune(a.Subject)))
+		}
+		text := a.Condition.Text
+		if icon := SeverityIcon(a.Condition.Severity); icon != "" {
+			text = icon + " " + text
+		}
+		texts[a.Subject] = append(texts[a.Subject], text)
+	}
+
+	// Only show times if the alerts were collected over more than a minute
+	withTime := alerts[len(alerts)-1].At.Sub(alerts[0].At) >= time.Minute
+	for _, subject := range subjects {
+		line := fmt.Sprintf("%-*s  %s", width, subject, strings.Join(texts[subject], "; "))
+		if withTime {
+			line = firstAlertAt(alerts, subject).Local().Format("15:04") + " " + line
+		}
+		body += line + "\n"
+	}
+	return title, body
+}
+
+// firstAlertAt returns when the first alert of subject was raised
+func firstAlertAt(alerts []QueuedAlert, subject string) time.Time {
+	for _, a := range alerts {
+		if a.Subject == subject {
+			return a.At
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,156 @@
+package rule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// changeClause identifies a period change condition by its reference and
+// threshold, e.g. "5d -10" or "since 2024-06-01 25", so entries on the same
+// window keep separate alert state
+func changeClause(pc config.PeriodChange, threshold float64) string {
+	var ref string
+	switch {
+	case pc.Days > 0:
+		ref = fmt.Sprintf("%dd", pc.Days)
+	case pc.Since != "":
+		ref = "since " + pc.Since
+	case pc.FromHigh > 0:
+		ref = fmt.Sprintf("high %dd", pc.FromHigh)
+	default:
+		ref = fmt.Sprintf("low %dd", pc.FromLow)
+	}
+	return fmt.Sprintf("%s %g", ref, threshold)
+}
+
+// ChangeWindow describes the reference of a period change, e.g. "over 5 trading days"
+func ChangeWindow(pc config.PeriodChange) string {
+	switch {
+	case pc.Days > 0:
+		return i18n.T("over %d trading days", pc.Days)
+	case pc.Since != "":
+		return i18n.T("since %s", pc.Since)
+	case pc.FromHigh > 0:
+		return i18n.T("from the %d-day high", pc.FromHigh)
+	default:
+		return i18n.T("from the %d-day low", pc.FromLow)
+	}
+}
+
+// changeLookback returns how many daily candles a period change needs
+func changeLookback(pc config.PeriodChange) int {
+	if pc.Since != "" {
+		// Calendar days over-fetch, plus a week to reach the close before the date
+		since, err := config.ParseDate(pc.Since)
+		if err != nil {
+			return 0
+		}
+		days := int(time.Since(since).Hours()/24) + 8
+		return min(max(days, 8), maxAnchorLookback)
+	}
+	return max(pc.Days, pc.FromHigh, pc.FromLow) + 2
+}
+
+// changeReference returns the price a period change is measured from and the
+// day it was set, false if the candles do not reach back far enough
+func changeReference(pc config.PeriodChange, c *stock.Candle, q *stock.Quote) (ref float64, at time.Time, ok bool) {
+	n := completedBars(c, q)
+	if c != nil {
+		n = min(n, len(c.C))
+	}
+	barDay := func(i int) time.Time { return time.Unix(c.T[i], 0).UTC() }
+
+	switch {
+	case pc.Days > 0:
+		if n < pc.Days {
+			return 0, time.Time{}, false
+		}
+		i := n - pc.Days
+		return c.C[i], barDay(i), c.C[i] > 0
+	case pc.Since != "":
+		for i := n - 1; i >= 0; i-- {
+			if barDay(i).Format(config.DateLayout) < pc.Since {
+				return c.C[i], barDay(i), c.C[i] > 0
+			}
+		}
+		return 0, time.Time{}, false
+	case pc.FromHigh > 0:
+		if n < pc.FromHigh {
+			return 0, time.Time{}, false
+		}
+		ref, at = q.High, quoteTime(q)
+		for i := n - pc.FromHigh; i < n; i++ {
+			if c.H[i] > ref {
+				ref, at = c.H[i], barDay(i)
+			}
+		}
+		return ref, at, ref > 0
+	default:
+		if n < pc.FromLow {
+			return 0, time.Time{}, false
+		}
+		ref, at = q.Low, quoteTime(q)
+		for i := n - pc.FromLow; i < n; i++ {
+			if ref <= 0 || c.L[i] < ref {
+				ref, at = c.L[i], barDay(i)
+			}
+		}
+		return ref, at, ref > 0
+	}
+}
+
+// changeCandles returns the daily candles to measure changes on, falling back
+// to the bars built from recorded quotes
+func changeCandles(in Input) *stock.Candle {
+	if in.Candles != nil {
+		return in.Candles
+	}
+	return in.LocalCandles
+}
+
+// periodChange returns the change in percent of the quote vs the reference of pc
+func periodChange(pc config.PeriodChange, c *stock.Candle, q *stock.Quote) (change, ref float64, at time.Time, ok bool) {
+	if ref, at, ok = changeReference(pc, c, q); !ok || q.CurrentPrice <= 0 {
+		return 0, 0, time.Time{}, false
+	}
+	return (q.CurrentPrice - ref) / ref * 100, ref, at, true
+}
+
+// evaluateChanges adds the conditions on the change over several days, since a
+// date or from an N-day high/low
+func evaluateChanges(result *TriggerResult, r *config.Rule, in Input) {
+	for _, pc := range r.Changes {
+		change, ref, at, ok := periodChange(pc, changeCandles(in), in.Quote)
+		if !ok {
+			continue
+		}
+		window, day := ChangeWindow(pc), at.Format(config.DateLayout)
+		if pc.Above != nil && change > *pc.Above {
+			result.addClause(CondPeriodChangeAbove, changeClause(pc, *pc.Above), *pc.Above, change,
+				i18n.T("Change %+.2f%% %s, above %.2f%% (reference $%.2f on %s)", change, window, *pc.Above, ref, day))
+		}
+		if pc.Below != nil && change < *pc.Below {
+			result.addClause(CondPeriodChangeBelow, changeClause(pc, *pc.Below), *pc.Below, change,
+				i18n.T("Change %+.2f%% %s, below %.2f%% (reference $%.2f on %s)", change, window, *pc.Below, ref, day))
+		}
+	}
+}
+
+// observeChange returns the current change of the period change condition c
+func observeChange(r *config.Rule, c Condition, in Input) (v float64, ok bool) {
+	for _, pc := range r.Changes {
+		threshold := pc.Above
+		if c.Type == CondPeriodChangeBelow {
+			threshold = pc.Below
+		}
+		if threshold != nil && changeClause(pc, *threshold) == c.Clause {
+			v, _, _, ok = periodChange(pc, changeCandles(in), in.Quote)
+			return v, ok
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,143 @@
+package rule
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/indicator"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// crossDirection compares the last two bars where both series are known and
+// returns +1 if a crossed above b, -1 if it crossed below, and 0 otherwise
+func crossDirection(a, b []float64) int {
+	diff := make([]float64, len(a))
+	for i := range a {
+		if i < len(b) {
+			diff[i] = a[i] - b[i]
+		} else {
+			diff[i] = math.NaN()
+		}
+	}
+	prev, cur, ok := indicator.LastTwo(diff)
+	switch {
+	case !ok:
+		return 0
+	case prev <= 0 && cur > 0:
+		return 1
+	case prev >= 0 && cur < 0:
+		return -1
+	}
+	return 0
+}
+
+// level returns a series of n copies of v
+func level(v float64, n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = v
+	}
+	return s
+}
+
+// crossoverCandles returns the candles crossovers are computed from, with the
+// daily bar updated to the live quote
+func crossoverCandles(x *config.Crossovers, in Input) *stock.Candle {
+	res := x.GetResolution()
+	if res == "D" {
+		return liveCandles(in.Candles, in.Quote)
+	}
+	return in.Intraday[res]
+}
+
+// evaluateCrossovers adds a condition for every crossover on the latest bar
+func evaluateCrossovers(result *TriggerResult, x *config.Crossovers, in Input) {
+	c := crossoverCandles(x, in)
+	if c == nil || len(c.C) < 2 {
+		return
+	}
+	closes := indicator.Closes(c)
+	last := func(s []float64) float64 {
+		v, _ := indicator.Last(s)
+		return v
+	}
+	add := func(clause string, threshold, value float64, text string) {
+		result.Conditions = append(result.Conditions, Condition{
+			RuleID:    result.Rule.ID,
+			Type:      CondCross,
+			Threshold: threshold,
+			Clause:    clause,
+			Value:     value,
+			Text:      text,
+			Severity:  result.Rule.SeverityOf(CondCross),
+		})
+	}
+	bar := ""
+	if res := x.GetResolution(); res != "D" {
+		bar = i18n.T(" (%s-minute bars)", res)
+	}
+
+	// Price crossing a moving average
+	for _, n := range x.PriceSMA {
+		sma := indicator.SMA(closes, n)
+		switch crossDirection(closes, sma) {
+		case 1:
+			add(fmt.Sprintf("price_sma(%d) up", n), 0, last(sma),
+				i18n.T("Price crossed above SMA(%d) $%.2f%s", n, last(sma), bar))
+		case -1:
+			add(fmt.Sprintf("price_sma(%d) down", n), 0, last(sma),
+				i18n.T("Price crossed below SMA(%d) $%.2f%s", n, last(sma), bar))
+		}
+	}
+
+	// Golden and death cross
+	if len(x.SMACross) == 2 {
+		fast, slow := x.SMACross[0], x.SMACross[1]
+		switch crossDirection(indicator.SMA(closes, fast), indicator.SMA(closes, slow)) {
+		case 1:
+			add(fmt.Sprintf("sma_cross(%d,%d) up", fast, slow), 0, 0,
+				i18n.T("Golden cross: SMA(%d) crossed above SMA(%d)%s", fast, slow, bar))
+		case -1:
+			add(fmt.Sprintf("sma_cross(%d,%d) down", fast, slow), 0, 0,
+				i18n.T("Death cross: SMA(%d) crossed below SMA(%d)%s", fast, slow, bar))
+		}
+	}
+
+	// MACD crossing its signal line
+	if x.MACDSignal {
+		m := indicator.MACD(closes, 12, 26, 9)
+		switch crossDirection(m.MACD, m.Signal) {
+		case 1:
+			add("macd_signal up", 0, last(m.MACD),
+				i18n.T("MACD bullish cross: MACD %.3f crossed above signal %.3f%s", last(m.MACD), last(m.Signal), bar))
+		case -1:
+			add("macd_signal down", 0, last(m.MACD),
+				i18n.T("MACD bearish cross: MACD %.3f crossed below signal %.3f%s", last(m.MACD), last(m.Signal), bar))
+		}
+	}
+
+	// RSI entering or leaving the overbought and oversold zones
+	if x.RSI > 0 {
+		rsi := indicator.RSI(closes, x.RSI)
+		v := last(rsi)
+		overbought, oversold := x.RSILevels()
+		switch crossDirection(rsi, level(overbought, len(rsi))) {
+		case 1:
+			add(fmt.Sprintf("rsi(%d) enter overbought", x.RSI), overbought, v,
+				i18n.T("RSI(%d) %.2f entered overbought (> %.0f)%s", x.RSI, v, overbought, bar))
+		case -1:
+			add(fmt.Sprintf("rsi(%d) leave overbought", x.RSI), overbought, v,
+				i18n.T("RSI(%d) %.2f left overbought (< %.0f)%s", x.RSI, v, overbought, bar))
+		}
+		switch crossDirection(rsi, level(oversold, len(rsi))) {
+		case -1:
+			add(fmt.Sprintf("rsi(%d) enter oversold", x.RSI), oversold, v,
+				i18n.T("RSI(%d) %.2f entered oversold (< %.0f)%s", x.RSI, v, oversold, bar))
+		case 1:
+			add(fmt.Sprintf("rsi(%d) leave oversold", x.RSI), oversold, v,
+				i18n.T("RSI(%d) %.2f left oversold (> %.0f)%s", x.RSI, v, oversold, bar))
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package rule
+
+import (
+	"math"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/indicator"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// Input is the market data a rule is evaluated against
+type Input struct {
+	Quote   *stock.Quote
+	Candles *stock.Candle // Daily candles, needed by indicator conditions
+	// Daily bars built from recorded quotes, used by changes when Candles are missing
+	LocalCandles *stock.Candle
+	Holding      *config.Holding // Position in the symbol, if any
+	Peak         float64         // Trailing stop high-water mark, set by Tracker
+	Market       string          // Market of the symbol, used for session times
+
+	// Intraday candles by resolution (e.g. "15"), needed by intraday crossovers
+	Intraday map[string]*stock.Candle
+
+	// Other symbols referenced by pair rules, keyed by symbol
+	Legs map[string]*Leg
+}
+
+// Trailing stops and changes since a date look back at most this many days of candles
+const maxAnchorLookback = 750
+
+// CandleLookback returns how many daily candles the rule needs, 0 if none
+func CandleLookback(r *config.Rule, h *config.Holding) int {
+	lookback := 0
+	if r.Expr != nil {
+		lookback = r.Expr.Lookback()
+	}
+	if x := r.Crossovers; x != nil && x.GetResolution() == "D" {
+		lookback = max(lookback, crossoverLookback(x))
+	}
+	lookback = max(lookback, PairLookback(r))
+	if r.BreakPrevHigh || r.BreakPrevLow {
+		lookback = max(lookback, 3)
+	}
+	if days := max(r.NewHighDays, r.NewLowDays); days > 0 {
+		lookback = max(lookback, days+2)
+	}
+	for _, pc := range r.Changes {
+		lookback = max(lookback, changeLookback(pc))
+	}
+	if r.HasTrailingStop() {
+		// Enough bars to find the peak since the anchor date (calendar days over-fetch)
+		if anchor := trailingAnchor(r, h); !anchor.IsZero() {
+			days := int(time.Since(anchor).Hours()/24) + 1
+			lookback = max(lookback, min(days, maxAnchorLookback))
+		}
+	}
+	return lookback
+}
+
+// CandleNeeds returns how many candles the enabled rules need per resolution,
+// with "D" for daily candles. Resolutions that are not needed are omitted.
+func CandleNeeds(rules []*config.Rule, h *config.Holding) map[string]int {
+	needs := make(map[string]int)
+	for _, r := range rules {
+		if !r.IsEnabled() {
+			continue
+		}
+		if lookback := CandleLookback(r, h); lookback > 0 {
+			needs["D"] = max(needs["D"], lookback)
+		}
+		if x := r.Crossovers; x != nil && x.GetResolution() != "D" {
+			res := x.GetResolution()
+			needs[res] = max(needs[res], crossoverLookback(x))
+		}
+	}
+	return needs
+}
+
+// ChangeLookback returns how many daily candles the changes of the enabled
+// rules need, 0 if none has any. Only these can fall back to local bars.
+func ChangeLookback(rules []*config.Rule) int {
+	lookback := 0
+	for _, r := range rules {
+		if !r.IsEnabled() {
+			continue
+		}
+		for _, pc := range r.Changes {
+			lookback = max(lookback, changeLookback(pc))
+		}
+	}
+	return lookback
+}
+
+// crossoverLookback returns how many bars the crossovers need to warm up
+func crossoverLookback(x *config.Crossovers) int {
+	lookback := 0
+	for _, n := range x.PriceSMA {
+		lookback = max(lookback, n+2)
+	}
+	if len(x.SMACross) == 2 {
+		lookback = max(lookback, x.SMACross[1]+2)
+	}
+	if x.MACDSignal {
+		lookback = max(lookback, 100) // EMAs converge after a few multiples of their period
+	}
+	if x.RSI > 0 {
+		lookback = max(lookback, x.RSI*5+2)
+	}
+	return lookback
+}
+
+// trailingAnchor returns the date a trailing stop tracks its peak from: when
+// the holding was opened, else when the rule was created, else zero
+func trailingAnchor(r *config.Rule, h *config.Holding) time.Time {
+	if h != nil && h.Opened != "" {
+		if t, err := config.ParseDate(h.Opened); err == nil {
+			return t
+		}
+	}
+	t, _ := config.ParseDate(r.Created)
+	return t
+}
+
+// quoteTime returns when the quote was taken, falling back to now
+func quoteTime(q *stock.Quote) time.Time {
+	if q.Timestamp == 0 {
+		return time.Now()
+	}
+	return time.Unix(q.Timestamp, 0)
+}
+
+// liveCandles returns daily candles with the latest bar updated to the live quote,
+// appending a bar if the quote belongs to a newer day than the last candle
+func liveCandles(c *stock.Candle, q *stock.Quote) *stock.Candle {
+	if c == nil || q == nil || q.CurrentPrice <= 0 {
+		return c
+	}
+
+	ts := quoteTime(q).Unix()
+	day := quoteTime(q).UTC().Format("2006-01-02")
+
+	live := &stock.Candle{
+		S: c.S,
+		T: append([]int64{}, c.T...),
+		O: append([]float64{}, c.O...),
+		H: append([]float64{}, c.H...),
+		L: append([]float64{}, c.L...),
+		C: append([]float64{}, c.C...),
+		V: append([]float64{}, c.V...),
+	}
+
+	n := len(live.C)
+	if n > 0 && n == len(live.T) && time.Unix(live.T[n-1], 0).UTC().Format("2006-01-02") == day {
+		live.C[n-1] = q.CurrentPrice
+		if n == len(live.H) && q.CurrentPrice > live.H[n-1] {
+			live.H[n-1] = q.CurrentPrice
+		}
+		if n == len(live.L) && q.CurrentPrice < live.L[n-1] {
+			live.L[n-1] = q.CurrentPrice
+		}
+		return live
+	}
+
+	high, low := q.High, q.Low
+	if high <= 0 {
+		high = q.CurrentPrice
+	}
+	if low <= 0 {
+		low = q.CurrentPrice
+	}
+	live.T = append(live.T, ts)
+	live.O = append(live.O, q.Open)
+	live.H = append(live.H, high)
+	live.L = append(live.L, low)
+	live.C = append(live.C, q.CurrentPrice)
+	live.V = append(live.V, q.Volume)
+	return live
+}
+
+// env exposes an Input to when expressions
+type env struct {
+	in      Input
+	candles *stock.Candle
+	closes  []float64
+}
+
+func newEnv(in Input) *env {
+	e := &env{in: in, candles: liveCandles(in.Candles, in.Quote)}
+	if e.candles != nil {
+		e.closes = indicator.Closes(e.candles)
+	}
+	return e
+}
+
+func (e *env) Var(name string) float64 {
+	q, h := e.in.Quote, e.in.Holding
+	switch name {
+	case "price":
+		return q.CurrentPrice
+	case "open":
+		return q.Open
+	case "high":
+		return q.High
+	case "low":
+		return q.Low
+	case "prev_close":
+		return q.PrevClose
+	case "change":
+		return q.PercentChange
+	case "change_amount":
+		return q.Change
+	case "volume":
+		return q.Volume
+	}
+
+	// Holding variables are missing unless a position exists
+	if h == nil || h.Quantity <= 0 {
+		return math.NaN()
+	}
+	switch name {
+	case "cost":
+		return h.CostPrice
+	case "quantity":
+		return h.Quantity
+	case "gain":
+		return positionGain(q, h)
+	case "pl":
+		return positionPL(q, h)
+	}
+	return math.NaN()
+}
+
+// positionGain returns the percent gain of a holding vs its cost basis, NaN without a position
+func positionGain(q *stock.Quote, h *config.Holding) float64 {
+	if h == nil || h.Quantity <= 0 || h.CostPrice <= 0 || q.CurrentPrice <= 0 {
+		return math.NaN()
+	}
+	return (q.CurrentPrice - h.CostPrice) / h.CostPrice * 100
+}
+
+// positionPL returns the unrealised profit or loss of a holding, NaN without a position
+func positionPL(q *stock.Quote, h *config.Holding) float64 {
+	if h == nil || h.Quantity <= 0 || q.CurrentPrice <= 0 {
+		return math.NaN()
+	}
+	return (q.CurrentPrice - h.CostPrice) * h.Quantity
+}
+
+func (e *env) Call(name string, args []float64) float64 {
+	if e.candles == nil {
+		return math.NaN()
+	}
+
+	arg := func(i int, def float64) int {
+		if i < len(args) {
+			return int(args[i])
+		}
+		return int(def)
+	}
+	last := func(s []float64) float64 {
+		v, _ := indicator.Last(s)
+		return v
+	}
+
+	switch name {
+	case "sma":
+		return last(indicator.SMA(e.closes, arg(0, 0)))
+	case "ema":
+		return last(indicator.EMA(e.closes, arg(0, 0)))
+	case "rsi":
+		return last(indicator.RSI(e.closes, arg(0, 0)))
+	case "atr":
+		return last(indicator.ATR(e.candles, arg(0, 0)))
+	case "bb_upper", "bb_lower":
+		k := 2.0
+		if len(args) > 1 {
+			k = args[1]
+		}
+		bb := indicator.Bollinger(e.closes, arg(0, 20), k)
+		if name == "bb_upper" {
+			return last(bb.Upper)
+		}
+		return last(bb.Lower)
+	case "macd", "macd_signal", "macd_hist":
+		m := indicator.MACD(e.closes, arg(0, 12), arg(1, 26), arg(2, 9))
+		switch name {
+		case "macd":
+			return last(m.MACD)
+		case "macd_signal":
+			return last(m.Signal)
+		default:
+			return last(m.Histogram)
+		}
+	}
+	return math.NaN()
+}
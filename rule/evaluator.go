@@ -2,21 +2,137 @@ package rule
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
 	"github.com/congregalis/stock-ping/stock"
 )
 
+// Condition types reported in TriggerResult.Conditions. New types must also be
+// listed in config's ruleConditionTypes to be accepted in condition_severity.
+const (
+	CondPriceAbove         = "price_above"
+	CondPriceBelow         = "price_below"
+	CondChangeAbove        = "change_above"
+	CondChangeBelow        = "change_below"
+	CondLimitUp            = "limit_up"
+	CondLimitDown          = "limit_down"
+	CondNearLimitUp        = "near_limit_up"
+	CondNearLimitDown      = "near_limit_down"
+	CondGainAbove          = "gain_above"
+	CondLossBelow          = "loss_below"
+	CondPLAbove            = "pl_above"
+	CondPLBelow            = "pl_below"
+	CondTrailingStopPct    = "trailing_stop_pct"
+	CondTrailingStopAmount = "trailing_stop_amount"
+	CondGapUp              = "gap_up"
+	CondGapDown            = "gap_down"
+	CondNewDayHigh         = "new_day_high"
+	CondNewDayLow          = "new_day_low"
+	CondBreakPrevHigh      = "break_prev_high"
+	CondBreakPrevLow       = "break_prev_low"
+	CondNewHighDays        = "new_high_days"
+	CondNewLowDays         = "new_low_days"
+	CondPeriodChangeAbove  = "period_change_above"
+	CondPeriodChangeBelow  = "period_change_below"
+	CondCross              = "cross"
+	CondPairAbove          = "pair_above"
+	CondPairBelow          = "pair_below"
+	CondPairZScore         = "pair_zscore"
+	CondWhen               = "when"
+)
+
+// Condition is a single rule condition that was met
+type Condition struct {
+	RuleID    string  `json:"rule_id"`
+	Type      string  `json:"type"`                // One of the Cond* constants
+	Threshold float64 `json:"threshold,omitempty"` // Configured threshold, 0 if the condition has none
+	Clause    string  `json:"clause,omitempty"`    // Expression clause or crossover, e.g. "price_sma(50) up"
+	Value     float64 `json:"value,omitempty"`     // Observed value that met the condition
+	Text      string  `json:"text,omitempty"`      // Display text including live values
+
+	Severity config.Severity `json:"severity,omitempty"` // Severity configured for the condition
+}
+
+// Key identifies the condition independently of the live values, so the
+// same condition staying true across ticks is recognised as unchanged
+func (c Condition) Key() string {
+	if c.Clause != "" {
+		return fmt.Sprintf("%s/%s/%s", c.RuleID, c.Type, c.Clause)
+	}
+	return fmt.Sprintf("%s/%s/%g", c.RuleID, c.Type, c.Threshold)
+}
+
 // TriggerResult represents the result of a rule evaluation
 type TriggerResult struct {
-	Rule    *config.Rule
-	Quote   *stock.Quote
-	Reasons []string // List of trigger reasons
+	Rule       *config.Rule
+	Quote      *stock.Quote
+	Holding    *config.Holding // Position in the symbol, if any
+	Conditions []Condition     // Conditions that were met
+
+	// Trailing stop level and the peak it trails, 0 if the rule has none
+	TrailingPeak float64
+	TrailingStop float64
 }
 
 // Triggered returns true if any conditions were triggered
 func (t *TriggerResult) Triggered() bool {
-	return len(t.Reasons) > 0
+	return len(t.Conditions) > 0
+}
+
+// Severity returns the highest severity of the conditions that were met
+func (t *TriggerResult) Severity() config.Severity {
+	return HighestSeverity(t.Conditions)
+}
+
+// HighestSeverity returns the most urgent severity among conditions, or info if there are none
+func HighestSeverity(conditions []Condition) config.Severity {
+	highest := config.SeverityInfo
+	for _, c := range conditions {
+		if c.Severity.Rank() > highest.Rank() {
+			highest = c.Severity
+		}
+	}
+	return highest
+}
+
+// SeverityIcon returns a marker for warning and critical alerts, and "" for info
+func SeverityIcon(s config.Severity) string {
+	switch s {
+	case config.SeverityWarning:
+		return "🟡"
+	case config.SeverityCritical:
+		return "🔴"
+	}
+	return ""
+}
+
+// Reasons returns the display text of each condition that was met
+func (t *TriggerResult) Reasons() []string {
+	reasons := make([]string, len(t.Conditions))
+	for i, c := range t.Conditions {
+		reasons[i] = c.Text
+	}
+	return reasons
+}
+
+func (t *TriggerResult) add(typ string, threshold, value float64, text string) {
+	t.Conditions = append(t.Conditions, Condition{
+		RuleID:    t.Rule.ID,
+		Type:      typ,
+		Threshold: threshold,
+		Value:     value,
+		Text:      text,
+		Severity:  t.Rule.SeverityOf(typ),
+	})
+}
+
+// addClause adds a condition keyed on clause rather than its threshold, for
+// levels that move while the condition is tracked
+func (t *TriggerResult) addClause(typ, clause string, threshold, value float64, text string) {
+	t.add(typ, threshold, value, text)
+	t.Conditions[len(t.Conditions)-1].Clause = clause
 }
 
 // FormatNotification returns a formatted notification message
@@ -26,23 +142,33 @@ func (t *TriggerResult) FormatNotification() (title, body string) {
 		displayName = fmt.Sprintf("%s (%s)", t.Rule.Symbol, t.Rule.Name)
 	}
 
-	title = fmt.Sprintf("📊 %s 触发提醒", displayName)
+	title = i18n.T("📊 %s alert", displayName)
 
-	changeSign := ""
-	if t.Quote.PercentChange >= 0 {
-		changeSign = "+"
-	}
+	body = i18n.T("Price: $%.2f (%+.2f%%)\n", t.Quote.CurrentPrice, t.Quote.PercentChange)
 
-	body = fmt.Sprintf("价格: $%.2f (%s%.2f%%)\n",
-		t.Quote.CurrentPrice, changeSign, t.Quote.PercentChange)
+	if pl := positionPL(t.Quote, t.Holding); !math.IsNaN(pl) {
+		body += i18n.T("Position P/L: %s", signedMoney(pl))
+		if gain := positionGain(t.Quote, t.Holding); !math.IsNaN(gain) {
+			body += fmt.Sprintf(" (%+.2f%%)", gain)
+		}
+		body += "\n"
+	}
 
-	for _, reason := range t.Reasons {
-		body += fmt.Sprintf("⚠️ %s\n", reason)
+	for _, c := range t.Conditions {
+		body += fmt.Sprintf("⚠️ %s\n", c.Text)
 	}
 
 	return title, body
 }
 
+// signedMoney formats an amount as "+$1.00" or "-$1.00"
+func signedMoney(v float64) string {
+	if v < 0 {
+		return i18n.T("-$%.2f", -v)
+	}
+	return i18n.T("+$%.2f", v)
+}
+
 // Evaluator evaluates monitoring rules against stock quotes
 type Evaluator struct{}
 
@@ -51,36 +177,146 @@ func NewEvaluator() *Evaluator {
 	return &Evaluator{}
 }
 
-// Evaluate checks if a quote triggers any conditions in the rule
-func (e *Evaluator) Evaluate(rule *config.Rule, quote *stock.Quote) *TriggerResult {
+// Evaluate checks if the input triggers any conditions in the rule
+func (e *Evaluator) Evaluate(rule *config.Rule, in Input) *TriggerResult {
+	quote := in.Quote
 	result := &TriggerResult{
 		Rule:    rule,
 		Quote:   quote,
-		Reasons: []string{},
+		Holding: in.Holding,
 	}
 
 	// Check price above threshold
 	if rule.PriceAbove != nil && quote.CurrentPrice > *rule.PriceAbove {
-		result.Reasons = append(result.Reasons,
-			fmt.Sprintf("价格 $%.2f 超过 $%.2f", quote.CurrentPrice, *rule.PriceAbove))
+		result.add(CondPriceAbove, *rule.PriceAbove, quote.CurrentPrice,
+			i18n.T("Price $%.2f above $%.2f", quote.CurrentPrice, *rule.PriceAbove))
 	}
 
 	// Check price below threshold
 	if rule.PriceBelow != nil && quote.CurrentPrice < *rule.PriceBelow {
-		result.Reasons = append(result.Reasons,
-			fmt.Sprintf("价格 $%.2f 低于 $%.2f", quote.CurrentPrice, *rule.PriceBelow))
+		result.add(CondPriceBelow, *rule.PriceBelow, quote.CurrentPrice,
+			i18n.T("Price $%.2f below $%.2f", quote.CurrentPrice, *rule.PriceBelow))
 	}
 
 	// Check percent change above threshold (positive)
 	if rule.ChangeAbove != nil && quote.PercentChange > *rule.ChangeAbove {
-		result.Reasons = append(result.Reasons,
-			fmt.Sprintf("涨幅 %.2f%% 超过 %.2f%%", quote.PercentChange, *rule.ChangeAbove))
+		result.add(CondChangeAbove, *rule.ChangeAbove, quote.PercentChange,
+			i18n.T("Up %.2f%%, more than %.2f%%", quote.PercentChange, *rule.ChangeAbove))
 	}
 
 	// Check percent change below threshold (negative)
 	if rule.ChangeBelow != nil && quote.PercentChange < *rule.ChangeBelow {
-		result.Reasons = append(result.Reasons,
-			fmt.Sprintf("跌幅 %.2f%% 超过 %.2f%%", quote.PercentChange, *rule.ChangeBelow))
+		result.add(CondChangeBelow, *rule.ChangeBelow, quote.PercentChange,
+			i18n.T("Down %.2f%%, more than %.2f%%", quote.PercentChange, *rule.ChangeBelow))
+	}
+
+	// Check price limit bands (A-shares only)
+	if quote.HasLimits() {
+		atLimitUp := quote.CurrentPrice >= quote.LimitUp
+		atLimitDown := quote.CurrentPrice <= quote.LimitDown
+
+		if rule.LimitHit && atLimitUp {
+			result.add(CondLimitUp, quote.LimitUp, quote.CurrentPrice,
+				i18n.T("Limit up ¥%.2f", quote.LimitUp))
+		}
+		if rule.LimitHit && atLimitDown {
+			result.add(CondLimitDown, quote.LimitDown, quote.CurrentPrice,
+				i18n.T("Limit down ¥%.2f", quote.LimitDown))
+		}
+
+		// Approaching a limit, measured as distance from the current price
+		if rule.LimitNear != nil && quote.CurrentPrice > 0 {
+			toUp := (quote.LimitUp - quote.CurrentPrice) / quote.CurrentPrice * 100
+			toDown := (quote.CurrentPrice - quote.LimitDown) / quote.CurrentPrice * 100
+			if !atLimitUp && toUp <= *rule.LimitNear {
+				result.add(CondNearLimitUp, *rule.LimitNear, toUp,
+					i18n.T("Limit up ¥%.2f only %.2f%% away", quote.LimitUp, toUp))
+			}
+			if !atLimitDown && toDown <= *rule.LimitNear {
+				result.add(CondNearLimitDown, *rule.LimitNear, toDown,
+					i18n.T("Limit down ¥%.2f only %.2f%% away", quote.LimitDown, toDown))
+			}
+		}
+	}
+
+	// Check gain/loss vs the holding's cost basis
+	if gain := positionGain(quote, in.Holding); !math.IsNaN(gain) {
+		if rule.GainAbove != nil && gain > *rule.GainAbove {
+			result.add(CondGainAbove, *rule.GainAbove, gain,
+				i18n.T("Gain %.2f%% above %.2f%%", gain, *rule.GainAbove))
+		}
+		if rule.LossBelow != nil && gain < *rule.LossBelow {
+			result.add(CondLossBelow, *rule.LossBelow, gain,
+				i18n.T("Loss %.2f%% below %.2f%%", gain, *rule.LossBelow))
+		}
+	}
+
+	// Check absolute position P/L
+	if pl := positionPL(quote, in.Holding); !math.IsNaN(pl) {
+		if rule.PLAbove != nil && pl > *rule.PLAbove {
+			result.add(CondPLAbove, *rule.PLAbove, pl,
+				i18n.T("Position P/L %s above %s", signedMoney(pl), signedMoney(*rule.PLAbove)))
+		}
+		if rule.PLBelow != nil && pl < *rule.PLBelow {
+			result.add(CondPLBelow, *rule.PLBelow, pl,
+				i18n.T("Position P/L %s below %s", signedMoney(pl), signedMoney(*rule.PLBelow)))
+		}
+	}
+
+	// Check trailing stops against the high-water mark
+	if rule.HasTrailingStop() && in.Peak > 0 && quote.CurrentPrice > 0 {
+		result.TrailingPeak = in.Peak
+		drop := in.Peak - quote.CurrentPrice
+		dropPct := drop / in.Peak * 100
+
+		// With both set, the tighter (higher) stop applies
+		if rule.TrailingStopPct != nil {
+			stop := in.Peak * (1 - *rule.TrailingStopPct/100)
+			result.TrailingStop = max(result.TrailingStop, stop)
+			if dropPct >= *rule.TrailingStopPct {
+				result.add(CondTrailingStopPct, *rule.TrailingStopPct, dropPct,
+					i18n.T("Fell from peak $%.2f by %.2f%%, below trailing stop $%.2f", in.Peak, dropPct, stop))
+			}
+		}
+		if rule.TrailingStopAmount != nil {
+			stop := in.Peak - *rule.TrailingStopAmount
+			result.TrailingStop = max(result.TrailingStop, stop)
+			if drop >= *rule.TrailingStopAmount {
+				result.add(CondTrailingStopAmount, *rule.TrailingStopAmount, drop,
+					i18n.T("Fell from peak $%.2f by $%.2f, below trailing stop $%.2f", in.Peak, drop, stop))
+			}
+		}
+	}
+
+	// Check gaps, new highs/lows and range breakouts
+	evaluateRanges(result, rule, in)
+
+	// Check the change over several days or since a date
+	evaluateChanges(result, rule, in)
+
+	// Check the spread against a second symbol
+	if rule.Pair != nil {
+		evaluatePair(result, rule, in)
+	}
+
+	// Check indicator crossovers on the latest bar
+	if rule.Crossovers != nil {
+		evaluateCrossovers(result, rule.Crossovers, in)
+	}
+
+	// Check when expression, reporting each sub-clause that fired
+	if rule.Expr != nil {
+		if ok, clauses := rule.Expr.Eval(newEnv(in)); ok {
+			for _, clause := range clauses {
+				result.Conditions = append(result.Conditions, Condition{
+					RuleID:   rule.ID,
+					Type:     CondWhen,
+					Clause:   clause.Source,
+					Text:     i18n.T("Met %s", clause.Text),
+					Severity: rule.SeverityOf(CondWhen),
+				})
+			}
+		}
 	}
 
 	return result
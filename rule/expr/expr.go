@@ -0,0 +1,764 @@
+// Package expr parses, type-checks and evaluates rule condition expressions
+// such as `price > sma(50) && rsi(14) < 30 || change < -3`.
+//
+// Expressions are compiled once at config load. Evaluation is pure: the
+// caller supplies variables and indicator functions through an Env.
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Env supplies values for variables and functions during evaluation.
+// Missing data should be reported as NaN, which makes comparisons false.
+type Env interface {
+	Var(name string) float64
+	Call(name string, args []float64) float64
+}
+
+// Variables that may be referenced in an expression
+var Variables = map[string]string{
+	"price":         "current price",
+	"open":          "today's open",
+	"high":          "today's high",
+	"low":           "today's low",
+	"prev_close":    "previous close",
+	"change":        "percent change vs previous close",
+	"change_amount": "change vs previous close",
+	"volume":        "volume",
+	"cost":          "holding cost price",
+	"quantity":      "holding quantity",
+	"gain":          "holding P/L percent vs cost",
+	"pl":            "holding P/L amount",
+}
+
+// funcSig describes a function's arity and how much candle history it needs
+type funcSig struct {
+	minArgs, maxArgs int
+	// lookback returns the number of daily bars needed for the given constant args
+	lookback func(args []float64) int
+}
+
+func period(i, def int) func(args []float64) int {
+	return func(args []float64) int {
+		if i < len(args) {
+			return int(args[i])
+		}
+		return def
+	}
+}
+
+func macdLookback(args []float64) int {
+	slow, signal := 26, 9
+	if len(args) > 1 {
+		slow = int(args[1])
+	}
+	if len(args) > 2 {
+		signal = int(args[2])
+	}
+	return slow + signal
+}
+
+// Functions that may be called in an expression. Indicator periods must be
+// positive integer literals so the required candle history is known up front.
+var Functions = map[string]funcSig{
+	"sma":         {1, 1, period(0, 0)},
+	"ema":         {1, 1, func(a []float64) int { return int(a[0]) * 3 }}, // Extra bars so the EMA converges
+	"rsi":         {1, 1, func(a []float64) int { return int(a[0]) * 3 }},
+	"atr":         {1, 1, func(a []float64) int { return int(a[0]) * 3 }},
+	"bb_upper":    {1, 2, period(0, 20)},
+	"bb_lower":    {1, 2, period(0, 20)},
+	"macd":        {0, 3, macdLookback},
+	"macd_signal": {0, 3, macdLookback},
+	"macd_hist":   {0, 3, macdLookback},
+	"abs":         {1, 1, nil},
+	"min":         {2, 2, nil},
+	"max":         {2, 2, nil},
+}
+
+// Error is a compile error at a byte offset within the expression
+type Error struct {
+	Pos int // 0-based byte offset
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos+1, e.Msg)
+}
+
+// Expr is a compiled, type-checked boolean expression
+type Expr struct {
+	src      string
+	root     node
+	lookback int
+}
+
+// String returns the expression source
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Lookback returns the number of daily candles the expression needs, 0 if none
+func (e *Expr) Lookback() int {
+	return e.lookback
+}
+
+// Compile parses and type-checks an expression, which must be boolean
+func Compile(src string) (*Expr, error) {
+	p := &parser{src: src}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &Error{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+
+	typ, err := check(root)
+	if err != nil {
+		return nil, err
+	}
+	if typ != typeBool {
+		return nil, &Error{Pos: root.pos(), Msg: "expression must be a condition (e.g. price > 100), not a number"}
+	}
+
+	return &Expr{src: src, root: root, lookback: lookback(root)}, nil
+}
+
+// Clause is a sub-clause of an expression that held during evaluation
+type Clause struct {
+	Source string // Clause as written, e.g. "rsi(14) < 30"
+	Text   string // Clause with its current values, e.g. "rsi(14) (28.41) < 30"
+}
+
+// Eval evaluates the expression and returns whether it holds together with
+// the sub-clauses that made it true. An expression whose result depends on
+// missing data does not hold.
+func (e *Expr) Eval(env Env) (bool, []Clause) {
+	ok, clauses, _ := evalBool(e.root, env)
+	return ok, clauses
+}
+
+// ---- Lexer ----
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	num  float64
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	i      int
+}
+
+var operators = []string{"&&", "||", "<=", ">=", "==", "!=", "<", ">", "!", "+", "-", "*", "/"}
+
+func (p *parser) tokenize() error {
+	s := p.src
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			p.tokens = append(p.tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case (c >= '0' && c <= '9') || c == '.':
+			start := i
+			for i < len(s) && ((s[i] >= '0' && s[i] <= '9') || s[i] == '.') {
+				i++
+			}
+			v, err := strconv.ParseFloat(s[start:i], 64)
+			if err != nil {
+				return &Error{Pos: start, Msg: fmt.Sprintf("invalid number %q", s[start:i])}
+			}
+			p.tokens = append(p.tokens, token{kind: tokNumber, text: s[start:i], pos: start, num: v})
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			start := i
+			for i < len(s) && (s[i] == '_' || (s[i] >= 'a' && s[i] <= 'z') || (s[i] >= 'A' && s[i] <= 'Z') || (s[i] >= '0' && s[i] <= '9')) {
+				i++
+			}
+			word := strings.ToLower(s[start:i])
+			// Allow and/or/not as keyword aliases
+			switch word {
+			case "and":
+				p.tokens = append(p.tokens, token{kind: tokOp, text: "&&", pos: start})
+			case "or":
+				p.tokens = append(p.tokens, token{kind: tokOp, text: "||", pos: start})
+			case "not":
+				p.tokens = append(p.tokens, token{kind: tokOp, text: "!", pos: start})
+			default:
+				p.tokens = append(p.tokens, token{kind: tokIdent, text: word, pos: start})
+			}
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(s[i:], op) {
+					p.tokens = append(p.tokens, token{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return &Error{Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: tokEOF, text: "end of expression", pos: len(s)})
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+// ---- AST ----
+
+type node interface {
+	pos() int
+	text() string
+}
+
+// span is the source range of a node
+type span struct {
+	start, end int
+	src        string
+}
+
+func (s span) pos() int     { return s.start }
+func (s span) text() string { return s.src }
+
+func (p *parser) span(start, end int) span {
+	return span{start: start, end: end, src: strings.TrimSpace(p.src[start:end])}
+}
+
+type numberNode struct {
+	span
+	value float64
+}
+
+type varNode struct {
+	span
+	name string
+}
+
+type callNode struct {
+	span
+	name string
+	args []node
+}
+
+type unaryNode struct {
+	span
+	op      string
+	operand node
+}
+
+// parenNode keeps parentheses in the source span of a grouped expression
+type parenNode struct {
+	span
+	inner node
+}
+
+type binaryNode struct {
+	span
+	op          string
+	left, right node
+}
+
+// ---- Parser ----
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = p.binary("||", left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = p.binary("&&", left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isOp("!") {
+		t := p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{span: p.span(t.pos, endOf(operand)), op: "!", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("<", "<=", ">", ">=", "==", "!=") {
+		op := p.next().text
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		left = p.binary(op, left, right)
+		if p.isOp("<", "<=", ">", ">=", "==", "!=") {
+			t := p.peek()
+			return nil, &Error{Pos: t.pos, Msg: "comparisons cannot be chained, use && to combine them"}
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+", "-") {
+		op := p.next().text
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = p.binary(op, left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*", "/") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = p.binary(op, left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOp("-") {
+		t := p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		// Fold negative literals so "-3" renders and checks as a number
+		if n, ok := operand.(*numberNode); ok {
+			return &numberNode{span: p.span(t.pos, n.end), value: -n.value}, nil
+		}
+		return &unaryNode{span: p.span(t.pos, endOf(operand)), op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &numberNode{span: p.span(t.pos, t.pos+len(t.text)), value: t.num}, nil
+
+	case tokIdent:
+		if p.peek().kind != tokLParen {
+			return &varNode{span: p.span(t.pos, t.pos+len(t.text)), name: t.text}, nil
+		}
+		p.next() // (
+		call := &callNode{name: t.text}
+		if p.peek().kind != tokRParen {
+			for {
+				arg, err := p.parseSum()
+				if err != nil {
+					return nil, err
+				}
+				call.args = append(call.args, arg)
+				if p.peek().kind != tokComma {
+					break
+				}
+				p.next()
+			}
+		}
+		closing := p.next()
+		if closing.kind != tokRParen {
+			return nil, &Error{Pos: closing.pos, Msg: fmt.Sprintf("expected ) to close %s(, got %q", t.text, closing.text)}
+		}
+		call.span = p.span(t.pos, closing.pos+1)
+		return call, nil
+
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing := p.next()
+		if closing.kind != tokRParen {
+			return nil, &Error{Pos: closing.pos, Msg: fmt.Sprintf("expected ), got %q", closing.text)}
+		}
+		return &parenNode{span: p.span(t.pos, closing.pos+1), inner: inner}, nil
+
+	default:
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a value, got %q", t.text)}
+	}
+}
+
+func (p *parser) binary(op string, left, right node) node {
+	return &binaryNode{
+		span:  p.span(left.pos(), endOf(right)),
+		op:    op,
+		left:  left,
+		right: right,
+	}
+}
+
+func endOf(n node) int {
+	switch n := n.(type) {
+	case *numberNode:
+		return n.end
+	case *varNode:
+		return n.end
+	case *callNode:
+		return n.end
+	case *unaryNode:
+		return n.end
+	case *parenNode:
+		return n.end
+	case *binaryNode:
+		return n.end
+	}
+	return 0
+}
+
+// ---- Type checker ----
+
+type exprType int
+
+const (
+	typeNumber exprType = iota
+	typeBool
+)
+
+func (t exprType) String() string {
+	if t == typeBool {
+		return "condition"
+	}
+	return "number"
+}
+
+func check(n node) (exprType, error) {
+	switch n := n.(type) {
+	case *numberNode:
+		return typeNumber, nil
+
+	case *varNode:
+		if _, ok := Variables[n.name]; !ok {
+			return 0, &Error{Pos: n.start, Msg: fmt.Sprintf("unknown variable %q", n.name)}
+		}
+		return typeNumber, nil
+
+	case *callNode:
+		sig, ok := Functions[n.name]
+		if !ok {
+			return 0, &Error{Pos: n.start, Msg: fmt.Sprintf("unknown function %q", n.name)}
+		}
+		if len(n.args) < sig.minArgs || len(n.args) > sig.maxArgs {
+			want := fmt.Sprintf("%d", sig.minArgs)
+			if sig.maxArgs != sig.minArgs {
+				want = fmt.Sprintf("%d to %d", sig.minArgs, sig.maxArgs)
+			}
+			return 0, &Error{Pos: n.start, Msg: fmt.Sprintf("%s() takes %s arguments, got %d", n.name, want, len(n.args))}
+		}
+		for _, arg := range n.args {
+			if sig.lookback != nil {
+				// Indicator parameters must be positive literals
+				num, ok := arg.(*numberNode)
+				if !ok || num.value <= 0 {
+					return 0, &Error{Pos: arg.pos(), Msg: fmt.Sprintf("%s() parameters must be positive numbers", n.name)}
+				}
+				continue
+			}
+			t, err := check(arg)
+			if err != nil {
+				return 0, err
+			}
+			if t != typeNumber {
+				return 0, &Error{Pos: arg.pos(), Msg: fmt.Sprintf("%s() expects a number, got a condition", n.name)}
+			}
+		}
+		return typeNumber, nil
+
+	case *parenNode:
+		return check(n.inner)
+
+	case *unaryNode:
+		t, err := check(n.operand)
+		if err != nil {
+			return 0, err
+		}
+		want := typeNumber
+		if n.op == "!" {
+			want = typeBool
+		}
+		if t != want {
+			return 0, &Error{Pos: n.start, Msg: fmt.Sprintf("operator %s expects a %s, got a %s", n.op, want, t)}
+		}
+		return want, nil
+
+	case *binaryNode:
+		lt, err := check(n.left)
+		if err != nil {
+			return 0, err
+		}
+		rt, err := check(n.right)
+		if err != nil {
+			return 0, err
+		}
+		operand, result := typeNumber, typeNumber
+		switch n.op {
+		case "&&", "||":
+			operand, result = typeBool, typeBool
+		case "<", "<=", ">", ">=", "==", "!=":
+			result = typeBool
+		}
+		if lt != operand {
+			return 0, &Error{Pos: n.left.pos(), Msg: fmt.Sprintf("operator %s expects a %s on the left, got a %s", n.op, operand, lt)}
+		}
+		if rt != operand {
+			return 0, &Error{Pos: n.right.pos(), Msg: fmt.Sprintf("operator %s expects a %s on the right, got a %s", n.op, operand, rt)}
+		}
+		return result, nil
+	}
+	return 0, &Error{Pos: n.pos(), Msg: "invalid expression"}
+}
+
+func lookback(n node) int {
+	switch n := n.(type) {
+	case *callNode:
+		max := 0
+		if sig := Functions[n.name]; sig.lookback != nil {
+			args := make([]float64, len(n.args))
+			for i, a := range n.args {
+				args[i] = a.(*numberNode).value
+			}
+			max = sig.lookback(args)
+		}
+		for _, a := range n.args {
+			if l := lookback(a); l > max {
+				max = l
+			}
+		}
+		return max
+	case *parenNode:
+		return lookback(n.inner)
+	case *unaryNode:
+		return lookback(n.operand)
+	case *binaryNode:
+		l, r := lookback(n.left), lookback(n.right)
+		if l > r {
+			return l
+		}
+		return r
+	}
+	return 0
+}
+
+// ---- Evaluation ----
+
+func evalNumber(n node, env Env) float64 {
+	switch n := n.(type) {
+	case *numberNode:
+		return n.value
+	case *varNode:
+		return env.Var(n.name)
+	case *callNode:
+		args := make([]float64, len(n.args))
+		for i, a := range n.args {
+			args[i] = evalNumber(a, env)
+		}
+		switch n.name {
+		case "abs":
+			return math.Abs(args[0])
+		case "min":
+			return math.Min(args[0], args[1])
+		case "max":
+			return math.Max(args[0], args[1])
+		}
+		return env.Call(n.name, args)
+	case *parenNode:
+		return evalNumber(n.inner, env)
+	case *unaryNode:
+		return -evalNumber(n.operand, env)
+	case *binaryNode:
+		l, r := evalNumber(n.left, env), evalNumber(n.right, env)
+		switch n.op {
+		case "+":
+			return l + r
+		case "-":
+			return l - r
+		case "*":
+			return l * r
+		case "/":
+			if r == 0 {
+				return math.NaN()
+			}
+			return l / r
+		}
+	}
+	return math.NaN()
+}
+
+// evalBool evaluates a condition and returns whether it holds, the clauses
+// that made it true, and whether the result is unknown because of missing
+// data. Unknown results never hold, also not under "!": "&&" and "||" are
+// only known when the known side decides them, e.g. false && unknown.
+func evalBool(n node, env Env) (ok bool, clauses []Clause, missing bool) {
+	switch n := n.(type) {
+	case *parenNode:
+		return evalBool(n.inner, env)
+
+	case *unaryNode: // "!"
+		ok, _, missing := evalBool(n.operand, env)
+		if ok || missing {
+			return false, nil, missing
+		}
+		return true, []Clause{{Source: n.text(), Text: n.text()}}, false
+
+	case *binaryNode:
+		switch n.op {
+		case "&&":
+			lok, lclauses, lmissing := evalBool(n.left, env)
+			if !lok && !lmissing {
+				return false, nil, false
+			}
+			rok, rclauses, rmissing := evalBool(n.right, env)
+			if !rok && !rmissing {
+				return false, nil, false
+			}
+			if lmissing || rmissing {
+				return false, nil, true
+			}
+			return true, append(lclauses, rclauses...), false
+		case "||":
+			// Evaluate both sides so every firing branch is reported
+			lok, lclauses, lmissing := evalBool(n.left, env)
+			rok, rclauses, rmissing := evalBool(n.right, env)
+			if lok || rok {
+				return true, append(lclauses, rclauses...), false
+			}
+			return false, nil, lmissing || rmissing
+		}
+
+		l, r := evalNumber(n.left, env), evalNumber(n.right, env)
+		if math.IsNaN(l) || math.IsNaN(r) {
+			return false, nil, true // Missing data never fires
+		}
+		var ok bool
+		switch n.op {
+		case "<":
+			ok = l < r
+		case "<=":
+			ok = l <= r
+		case ">":
+			ok = l > r
+		case ">=":
+			ok = l >= r
+		case "==":
+			ok = l == r
+		case "!=":
+			ok = l != r
+		}
+		if !ok {
+			return false, nil, false
+		}
+		return true, []Clause{{
+			Source: n.text(),
+			Text:   fmt.Sprintf("%s %s %s", operandText(n.left, l), n.op, operandText(n.right, r)),
+		}}, false
+	}
+	return false, nil, false
+}
+
+// operandText renders an operand with its current value unless it is a literal
+func operandText(n node, value float64) string {
+	if _, ok := n.(*numberNode); ok {
+		return n.text()
+	}
+	return fmt.Sprintf("%s (%.2f)", n.text(), value)
+}
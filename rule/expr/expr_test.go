@@ -0,0 +1,66 @@
+package expr
+
+import (
+	"math"
+	"testing"
+)
+
+// testEnv serves fixed variables and function results, NaN when absent
+type testEnv struct {
+	vars  map[string]float64
+	calls map[string]float64
+}
+
+func (e testEnv) Var(name string) float64 {
+	if v, ok := e.vars[name]; ok {
+		return v
+	}
+	return math.NaN()
+}
+
+func (e testEnv) Call(name string, args []float64) float64 {
+	if v, ok := e.calls[name]; ok {
+		return v
+	}
+	return math.NaN()
+}
+
+func TestEvalMissingData(t *testing.T) {
+	noCandles := testEnv{vars: map[string]float64{"price": 100, "change": -4}}
+	withCandles := testEnv{
+		vars:  map[string]float64{"price": 100, "change": -4},
+		calls: map[string]float64{"sma": 110, "rsi": 50},
+	}
+
+	tests := []struct {
+		src  string
+		env  testEnv
+		want bool
+	}{
+		{"price > sma(50)", noCandles, false},
+		{"!(price > sma(50))", noCandles, false},
+		{"!(rsi(14) > 70)", noCandles, false},
+		{"!(rsi(14) > 70)", withCandles, true},
+		{"!(price > sma(50))", withCandles, true},
+		{"!!(rsi(14) > 70)", noCandles, false},
+		{"!(rsi(14) > 70 && change < -3)", noCandles, false},
+		{"!(rsi(14) > 70 && change > 0)", noCandles, true}, // change > 0 decides the &&
+		{"!(rsi(14) > 70 || change < -3)", noCandles, false},
+		{"!(rsi(14) > 70 || change > 0)", noCandles, false},
+		{"rsi(14) > 70 || change < -3", noCandles, true},
+		{"rsi(14) > 70 && change < -3", noCandles, false},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.src)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", tt.src, err)
+		}
+		got, clauses := e.Eval(tt.env)
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+		if !got && len(clauses) > 0 {
+			t.Errorf("Eval(%q) returned clauses %v without holding", tt.src, clauses)
+		}
+	}
+}
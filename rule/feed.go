@@ -0,0 +1,156 @@
+package rule
+
+import (
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// Feed-health conditions, raised by FeedMonitor rather than by a rule
+const (
+	CondFeedFailing   = "feed_failing"
+	CondFeedStale     = "feed_stale"
+	CondFeedRecovered = "feed_recovered"
+
+	// FeedRuleID is the rule ID of feed-health conditions
+	FeedRuleID = "feed"
+)
+
+// FeedStatus is the health of a symbol's quotes across refresh cycles
+type FeedStatus struct {
+	LastUpdate time.Time     // Last cycle with a fresh quote
+	Problem    string        // CondFeedFailing or CondFeedStale, "" while healthy
+	Since      time.Time     // First cycle of the current problem
+	Cycles     int           // Bad cycles in a row
+	QuoteAge   time.Duration // How long the quote has been stale in the session
+	Alerted    bool          // The problem was alerted, so its recovery is too
+}
+
+// FeedMonitor tracks the quotes of each watched symbol, raising a condition
+// when they keep failing or stay stale during the session, and another when
+// they recover. Problems are not raised during quiet hours.
+type FeedMonitor struct {
+	cfg    config.FeedHealthConfig
+	quiet  *config.QuietHours
+	status map[string]*FeedStatus
+}
+
+// NewFeedMonitor creates a feed monitor
+func NewFeedMonitor(cfg config.FeedHealthConfig, quiet *config.QuietHours) *FeedMonitor {
+	return &FeedMonitor{
+		cfg:    cfg,
+		quiet:  quiet,
+		status: make(map[string]*FeedStatus),
+	}
+}
+
+// Status returns the feed status of symbol, nil before its first cycle
+func (m *FeedMonitor) Status(symbol string) *FeedStatus {
+	return m.status[symbol]
+}
+
+// QuoteAge returns how long the quote has not been updated while the market
+// traded, 0 if the market is closed or the quote has no timestamp. Time before
+// a lunch break does not count once the market has resumed.
+func QuoteAge(market string, q *stock.Quote, now time.Time) time.Duration {
+	if q == nil || q.Timestamp == 0 || !stock.IsMarketOpenAt(market, now) {
+		return 0
+	}
+	// A quote from before the open, or the lunch break, is only late once
+	// trading has resumed for as long
+	from := time.Unix(q.Timestamp, 0)
+	if open := stock.SegmentOpen(market, now); from.Before(open) {
+		from = open
+	}
+	return max(now.Sub(from), 0)
+}
+
+// Observe records the result of fetching symbol's quote in a refresh cycle and
+// returns the feed-health conditions to alert on now
+func (m *FeedMonitor) Observe(symbol, market string, q *stock.Quote, err error, now time.Time) []Condition {
+	st := m.status[symbol]
+	if st == nil {
+		st = &FeedStatus{}
+		m.status[symbol] = st
+	}
+
+	problem := ""
+	st.QuoteAge = 0
+	if err != nil {
+		problem = CondFeedFailing
+	} else if st.QuoteAge = QuoteAge(market, q, now); st.QuoteAge > m.cfg.GetStaleAfter() {
+		problem = CondFeedStale
+	}
+
+	if problem == "" {
+		st.LastUpdate = now
+		if st.Problem == "" {
+			return nil
+		}
+		alerted, since := st.Alerted, st.Since
+		st.Problem, st.Cycles, st.Alerted = "", 0, false
+		if !alerted || !m.cfg.IsEnabled() {
+			return nil
+		}
+		down := config.Duration(now.Sub(since).Round(time.Minute))
+		return []Condition{m.condition(CondFeedRecovered, 0, config.SeverityInfo,
+			i18n.T("%s quotes recovered after %s", symbol, down))}
+	}
+
+	if st.Problem == "" {
+		st.Since = now
+	}
+	st.Problem = problem
+	st.Cycles++
+	if st.Alerted || st.Cycles < m.cfg.GetCycles() || !m.cfg.IsEnabled() {
+		return nil
+	}
+	if m.quiet != nil && m.quiet.Contains(now) {
+		return nil
+	}
+	st.Alerted = true
+
+	var text string
+	if problem == CondFeedFailing {
+		text = i18n.T("%s quotes failing for %d cycles (last update %s): %v", symbol, st.Cycles, formatLastUpdate(st.LastUpdate), err)
+	} else {
+		age := config.Duration(st.QuoteAge.Round(time.Minute))
+		text = i18n.T("%s quotes stale for %d cycles (quote from %s, %s old)", symbol, st.Cycles,
+			time.Unix(q.Timestamp, 0).Local().Format("01-02 15:04"), age)
+	}
+	return []Condition{m.condition(problem, st.Cycles, m.cfg.GetSeverity(), text)}
+}
+
+func (m *FeedMonitor) condition(typ string, cycles int, severity config.Severity, text string) Condition {
+	return Condition{
+		RuleID:    FeedRuleID,
+		Type:      typ,
+		Threshold: float64(m.cfg.GetCycles()),
+		Value:     float64(cycles),
+		Text:      text,
+		Severity:  severity,
+	}
+}
+
+// formatLastUpdate formats when a symbol last had a fresh quote
+func formatLastUpdate(t time.Time) string {
+	if t.IsZero() {
+		return i18n.T("never")
+	}
+	return t.Local().Format("01-02 15:04")
+}
+
+// FormatFeedNotification returns the notification of a feed-health condition
+func FormatFeedNotification(c Condition) (title, body string) {
+	switch c.Type {
+	case CondFeedRecovered:
+		title = i18n.T("✅ Quote feed recovered")
+	case CondFeedStale:
+		title = i18n.T("📡 Quote feed stale")
+	default:
+		title = i18n.T("📡 Quote feed failing")
+	}
+	return title, c.Text
+}
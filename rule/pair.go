@@ -0,0 +1,195 @@
+package rule
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// Leg is the market data of another symbol a pair rule refers to
+type Leg struct {
+	Quote   *stock.Quote
+	Candles *stock.Candle // Daily candles, needed by the z-score
+}
+
+// PairLookback returns how many daily candles each leg of the rule's pair needs, 0 if none
+func PairLookback(r *config.Rule) int {
+	if r.Pair == nil || r.Pair.ZScore <= 0 {
+		return 0
+	}
+	// Calendar days over-fetch, but holidays differ between the legs' markets
+	return r.Pair.GetWindow()*2 + 5
+}
+
+// pairSpread applies the pair's formula to leg A, leg B and the FX rate
+func pairSpread(p *config.Pair, a, b, fx float64) float64 {
+	b *= p.GetMultiplier() * fx
+	if a <= 0 || b <= 0 {
+		return math.NaN()
+	}
+	switch p.GetFormula() {
+	case config.PairDiff:
+		return a - b
+	case config.PairSpreadPct:
+		return (a - b) / b * 100
+	default:
+		return a / b
+	}
+}
+
+// legPrices returns the live leg B price and FX rate, false if a quote is missing
+func legPrices(p *config.Pair, in Input) (b, fx float64, ok bool) {
+	leg := in.Legs[p.Symbol]
+	if leg == nil || leg.Quote == nil || leg.Quote.CurrentPrice <= 0 {
+		return 0, 0, false
+	}
+	fx = 1
+	if p.FX != "" {
+		rate := in.Legs[p.FX]
+		if rate == nil || rate.Quote == nil || rate.Quote.CurrentPrice <= 0 {
+			return 0, 0, false
+		}
+		fx = rate.Quote.CurrentPrice
+	}
+	return leg.Quote.CurrentPrice, fx, true
+}
+
+// currentSpread returns the spread from live quotes, NaN if a leg is missing
+func currentSpread(p *config.Pair, in Input) float64 {
+	b, fx, ok := legPrices(p, in)
+	if !ok || in.Quote == nil {
+		return math.NaN()
+	}
+	return pairSpread(p, in.Quote.CurrentPrice, b, fx)
+}
+
+// dailyCloses maps each completed bar's UTC date to its close
+func dailyCloses(c *stock.Candle, today string) map[string]float64 {
+	closes := make(map[string]float64)
+	if c == nil {
+		return closes
+	}
+	for i := range min(len(c.T), len(c.C)) {
+		if day := time.Unix(c.T[i], 0).UTC().Format(config.DateLayout); day < today {
+			closes[day] = c.C[i]
+		}
+	}
+	return closes
+}
+
+// spreadHistory returns the spread at the close of the last window days on
+// which every leg traded, oldest first
+func spreadHistory(p *config.Pair, in Input) []float64 {
+	if in.Candles == nil || in.Legs[p.Symbol] == nil {
+		return nil
+	}
+	today := quoteTime(in.Quote).UTC().Format(config.DateLayout)
+	b := dailyCloses(in.Legs[p.Symbol].Candles, today)
+	var fx map[string]float64
+	if p.FX != "" {
+		if rate := in.Legs[p.FX]; rate != nil {
+			fx = dailyCloses(rate.Candles, today)
+		}
+	}
+
+	var spreads []float64
+	a := in.Candles
+	for i := min(len(a.T), len(a.C)) - 1; i >= 0 && len(spreads) < p.GetWindow(); i-- {
+		day := time.Unix(a.T[i], 0).UTC().Format(config.DateLayout)
+		if day >= today {
+			continue
+		}
+		bc, ok := b[day]
+		if !ok {
+			continue
+		}
+		rate := 1.0
+		if p.FX != "" {
+			if rate, ok = fx[day]; !ok {
+				continue
+			}
+		}
+		if s := pairSpread(p, a.C[i], bc, rate); !math.IsNaN(s) {
+			spreads = append([]float64{s}, spreads...)
+		}
+	}
+	return spreads
+}
+
+// pairZScore returns how many standard deviations the live spread is from the
+// rolling mean, with the mean and the spread, false without enough history
+func pairZScore(p *config.Pair, in Input) (z, mean, spread float64, ok bool) {
+	spread = currentSpread(p, in)
+	history := spreadHistory(p, in)
+	if math.IsNaN(spread) || len(history) < p.GetWindow() {
+		return 0, 0, spread, false
+	}
+	for _, s := range history {
+		mean += s
+	}
+	mean /= float64(len(history))
+	variance := 0.0
+	for _, s := range history {
+		variance += (s - mean) * (s - mean)
+	}
+	std := math.Sqrt(variance / float64(len(history)-1))
+	if std == 0 {
+		return 0, mean, spread, false
+	}
+	return (spread - mean) / std, mean, spread, true
+}
+
+// formatSpread formats a spread value for the pair's formula
+func formatSpread(p *config.Pair, v float64) string {
+	switch p.GetFormula() {
+	case config.PairSpreadPct:
+		return i18n.T("%.2f%%", v)
+	case config.PairDiff:
+		return i18n.T("%.2f", v)
+	default:
+		return i18n.T("%.4f", v)
+	}
+}
+
+// spreadName returns the display name of the pair's formula
+func spreadName(p *config.Pair) string {
+	switch p.GetFormula() {
+	case config.PairSpreadPct:
+		return i18n.T("premium")
+	case config.PairDiff:
+		return i18n.T("spread")
+	default:
+		return i18n.T("ratio")
+	}
+}
+
+// evaluatePair adds threshold and z-score conditions on the pair's spread
+func evaluatePair(result *TriggerResult, r *config.Rule, in Input) {
+	p := r.Pair
+	spread := currentSpread(p, in)
+	if math.IsNaN(spread) {
+		return
+	}
+	label := fmt.Sprintf("%s/%s %s", r.Symbol, p.Symbol, spreadName(p))
+
+	if p.Above != nil && spread > *p.Above {
+		result.add(CondPairAbove, *p.Above, spread,
+			i18n.T("%s %s above %s", label, formatSpread(p, spread), formatSpread(p, *p.Above)))
+	}
+	if p.Below != nil && spread < *p.Below {
+		result.add(CondPairBelow, *p.Below, spread,
+			i18n.T("%s %s below %s", label, formatSpread(p, spread), formatSpread(p, *p.Below)))
+	}
+
+	if p.ZScore > 0 {
+		if z, mean, _, ok := pairZScore(p, in); ok && math.Abs(z) > p.ZScore {
+			result.add(CondPairZScore, p.ZScore, z,
+				i18n.T("%s %s is %[5]+.2f standard deviations from its %[3]d-day mean %[4]s", label, formatSpread(p, spread),
+					p.GetWindow(), formatSpread(p, mean), z))
+		}
+	}
+}
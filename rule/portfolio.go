@@ -0,0 +1,362 @@
+package rule
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// Portfolio condition types, also listed in config's portfolioConditionTypes
+const (
+	CondPortfolioValueAbove     = "portfolio_value_above"
+	CondPortfolioValueBelow     = "portfolio_value_below"
+	CondPortfolioPLAbove        = "portfolio_pl_above"
+	CondPortfolioPLBelow        = "portfolio_pl_below"
+	CondPortfolioPLPctAbove     = "portfolio_pl_pct_above"
+	CondPortfolioPLPctBelow     = "portfolio_pl_pct_below"
+	CondPortfolioDayChangeAbove = "portfolio_day_change_above"
+	CondPortfolioDayChangeBelow = "portfolio_day_change_below"
+)
+
+// Position is a holding valued at its latest quote
+type Position struct {
+	Symbol    string
+	Quantity  float64
+	CostPrice float64
+	Price     float64
+	PrevClose float64
+}
+
+// Value returns the market value of the position
+func (p Position) Value() float64 {
+	return p.Quantity * p.Price
+}
+
+// PL returns the profit or loss vs the cost basis
+func (p Position) PL() float64 {
+	return p.Quantity * (p.Price - p.CostPrice)
+}
+
+// DayChange returns today's change in value, 0 without a previous close
+func (p Position) DayChange() float64 {
+	if p.PrevClose <= 0 {
+		return 0
+	}
+	return p.Quantity * (p.Price - p.PrevClose)
+}
+
+// Portfolio is the holdings valued at their latest quotes. Prices are summed
+// as quoted, without currency conversion.
+type Portfolio struct {
+	Positions []Position
+	Missing   []string // Symbols of holdings without a quote
+}
+
+// NewPortfolio values holdings at quotes, listing holdings without a quote in Missing
+func NewPortfolio(holdings []config.Holding, quotes map[string]*stock.Quote) *Portfolio {
+	p := &Portfolio{}
+	for _, h := range holdings {
+		if h.Quantity <= 0 {
+			continue
+		}
+		q := quotes[h.Symbol]
+		if q == nil || q.CurrentPrice <= 0 {
+			p.Missing = append(p.Missing, h.Symbol)
+			continue
+		}
+		p.Positions = append(p.Positions, Position{
+			Symbol:    h.Symbol,
+			Quantity:  h.Quantity,
+			CostPrice: h.CostPrice,
+			Price:     q.CurrentPrice,
+			PrevClose: q.PrevClose,
+		})
+	}
+	return p
+}
+
+func (p *Portfolio) sum(f func(Position) float64) float64 {
+	total := 0.0
+	for _, pos := range p.Positions {
+		total += f(pos)
+	}
+	return total
+}
+
+// Value returns the total market value
+func (p *Portfolio) Value() float64 {
+	return p.sum(Position.Value)
+}
+
+// PL returns the total profit or loss vs the cost basis
+func (p *Portfolio) PL() float64 {
+	return p.sum(Position.PL)
+}
+
+// PLPercent returns the total P/L as a percentage of the cost basis, NaN without cost
+func (p *Portfolio) PLPercent() float64 {
+	cost := p.sum(func(pos Position) float64 { return pos.Quantity * pos.CostPrice })
+	if cost <= 0 {
+		return math.NaN()
+	}
+	return p.PL() / cost * 100
+}
+
+// DayChange returns today's change in total value
+func (p *Portfolio) DayChange() float64 {
+	return p.sum(Position.DayChange)
+}
+
+// DayChangePercent returns today's change as a percentage of the value at the
+// previous close, NaN without previous closes
+func (p *Portfolio) DayChangePercent() float64 {
+	prev := p.sum(func(pos Position) float64 {
+		if pos.PrevClose <= 0 {
+			return 0
+		}
+		return pos.Quantity * pos.PrevClose
+	})
+	if prev <= 0 {
+		return math.NaN()
+	}
+	return p.DayChange() / prev * 100
+}
+
+// Contribution is one position's share of a portfolio metric
+type Contribution struct {
+	Symbol string
+	Amount float64
+	Share  float64 // Percent of the portfolio total
+}
+
+// Contributors returns the n positions contributing most to metric, largest
+// absolute amount first
+func (p *Portfolio) Contributors(metric func(Position) float64, n int) []Contribution {
+	total := p.sum(metric)
+	contributions := make([]Contribution, 0, len(p.Positions))
+	for _, pos := range p.Positions {
+		c := Contribution{Symbol: pos.Symbol, Amount: metric(pos)}
+		if total != 0 {
+			c.Share = c.Amount / math.Abs(total) * 100
+		}
+		contributions = append(contributions, c)
+	}
+	slices.SortStableFunc(contributions, func(a, b Contribution) int {
+		return cmp.Compare(math.Abs(b.Amount), math.Abs(a.Amount))
+	})
+	return contributions[:min(n, len(contributions))]
+}
+
+// PortfolioResult is the result of evaluating a portfolio rule
+type PortfolioResult struct {
+	Rule       *config.PortfolioRule
+	Portfolio  *Portfolio
+	Conditions []Condition
+}
+
+// Triggered returns true if any conditions were triggered
+func (t *PortfolioResult) Triggered() bool {
+	return len(t.Conditions) > 0
+}
+
+// Severity returns the highest severity of the conditions that were met
+func (t *PortfolioResult) Severity() config.Severity {
+	return HighestSeverity(t.Conditions)
+}
+
+// Subject names the rule in digests, by its name or as the portfolio
+func (t *PortfolioResult) Subject() string {
+	if t.Rule.Name != "" {
+		return t.Rule.Name
+	}
+	return i18n.T("Portfolio")
+}
+
+func (t *PortfolioResult) add(typ string, threshold, value float64, text string) {
+	t.Conditions = append(t.Conditions, Condition{
+		RuleID:    t.Rule.ID,
+		Type:      typ,
+		Threshold: threshold,
+		Value:     value,
+		Text:      text,
+		Severity:  t.Rule.SeverityOf(typ),
+	})
+}
+
+// portfolioMetric returns the per-position metric behind a portfolio condition
+func portfolioMetric(typ string) (name string, metric func(Position) float64) {
+	switch typ {
+	case CondPortfolioValueAbove, CondPortfolioValueBelow:
+		return i18n.T("market value"), Position.Value
+	case CondPortfolioDayChangeAbove, CondPortfolioDayChangeBelow:
+		return i18n.T("today's P/L"), Position.DayChange
+	default:
+		return i18n.T("position P/L"), Position.PL
+	}
+}
+
+// FormatNotification returns a notification with the portfolio totals and the
+// positions contributing most to the triggered conditions
+func (t *PortfolioResult) FormatNotification() (title, body string) {
+	name := t.Rule.Name
+	if name == "" {
+		name = t.Rule.ID
+	}
+	title = i18n.T("💼 Portfolio alert: %s", name)
+
+	p := t.Portfolio
+	body = i18n.T("Total value: $%.2f\n", p.Value())
+	body += i18n.T("Total P/L: %s", signedMoney(p.PL()))
+	if pct := p.PLPercent(); !math.IsNaN(pct) {
+		body += fmt.Sprintf(" (%+.2f%%)", pct)
+	}
+	body += i18n.T("\nToday: %s", signedMoney(p.DayChange()))
+	if pct := p.DayChangePercent(); !math.IsNaN(pct) {
+		body += fmt.Sprintf(" (%+.2f%%)", pct)
+	}
+	body += "\n"
+
+	for _, c := range t.Conditions {
+		body += fmt.Sprintf("⚠️ %s\n", c.Text)
+	}
+
+	// Break down each metric behind the conditions once
+	seen := make(map[string]bool)
+	for _, c := range t.Conditions {
+		label, metric := portfolioMetric(c.Type)
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		body += i18n.T("Top contributors to %s:\n", label)
+		for _, contrib := range p.Contributors(metric, 3) {
+			body += fmt.Sprintf("  • %s %s (%.0f%%)\n", contrib.Symbol, signedMoney(contrib.Amount), contrib.Share)
+		}
+	}
+	return title, body
+}
+
+// EvaluatePortfolio checks a portfolio rule against the valued holdings
+func (e *Evaluator) EvaluatePortfolio(rule *config.PortfolioRule, p *Portfolio) *PortfolioResult {
+	result := &PortfolioResult{Rule: rule, Portfolio: p}
+	if len(p.Positions) == 0 {
+		return result
+	}
+
+	value := p.Value()
+	if rule.ValueAbove != nil && value > *rule.ValueAbove {
+		result.add(CondPortfolioValueAbove, *rule.ValueAbove, value,
+			i18n.T("Portfolio value $%.2f above $%.2f", value, *rule.ValueAbove))
+	}
+	if rule.ValueBelow != nil && value < *rule.ValueBelow {
+		result.add(CondPortfolioValueBelow, *rule.ValueBelow, value,
+			i18n.T("Portfolio value $%.2f below $%.2f", value, *rule.ValueBelow))
+	}
+
+	pl := p.PL()
+	if rule.PLAbove != nil && pl > *rule.PLAbove {
+		result.add(CondPortfolioPLAbove, *rule.PLAbove, pl,
+			i18n.T("Portfolio P/L %s above %s", signedMoney(pl), signedMoney(*rule.PLAbove)))
+	}
+	if rule.PLBelow != nil && pl < *rule.PLBelow {
+		result.add(CondPortfolioPLBelow, *rule.PLBelow, pl,
+			i18n.T("Portfolio P/L %s below %s", signedMoney(pl), signedMoney(*rule.PLBelow)))
+	}
+
+	if pct := p.PLPercent(); !math.IsNaN(pct) {
+		if rule.PLPctAbove != nil && pct > *rule.PLPctAbove {
+			result.add(CondPortfolioPLPctAbove, *rule.PLPctAbove, pct,
+				i18n.T("Portfolio return %.2f%% above %.2f%%", pct, *rule.PLPctAbove))
+		}
+		if rule.PLPctBelow != nil && pct < *rule.PLPctBelow {
+			result.add(CondPortfolioPLPctBelow, *rule.PLPctBelow, pct,
+				i18n.T("Portfolio return %.2f%% below %.2f%%", pct, *rule.PLPctBelow))
+		}
+	}
+
+	if pct := p.DayChangePercent(); !math.IsNaN(pct) {
+		if rule.DayChangeAbove != nil && pct > *rule.DayChangeAbove {
+			result.add(CondPortfolioDayChangeAbove, *rule.DayChangeAbove, pct,
+				i18n.T("Portfolio up %.2f%% today, more than %.2f%%", pct, *rule.DayChangeAbove))
+		}
+		if rule.DayChangeBelow != nil && pct < *rule.DayChangeBelow {
+			result.add(CondPortfolioDayChangeBelow, *rule.DayChangeBelow, pct,
+				i18n.T("Portfolio down %.2f%% today, more than %.2f%%", pct, *rule.DayChangeBelow))
+		}
+	}
+	return result
+}
+
+// observePortfolio is the observer of portfolio rule conditions
+func observePortfolio(c Condition, p *Portfolio) (v float64, above, percent, ok bool) {
+	switch c.Type {
+	case CondPortfolioValueAbove:
+		return p.Value(), true, false, true
+	case CondPortfolioValueBelow:
+		return p.Value(), false, false, true
+	case CondPortfolioPLAbove:
+		return p.PL(), true, false, true
+	case CondPortfolioPLBelow:
+		return p.PL(), false, false, true
+	case CondPortfolioPLPctAbove:
+		return p.PLPercent(), true, true, true
+	case CondPortfolioPLPctBelow:
+		return p.PLPercent(), false, true, true
+	case CondPortfolioDayChangeAbove:
+		return p.DayChangePercent(), true, true, true
+	case CondPortfolioDayChangeBelow:
+		return p.DayChangePercent(), false, true, true
+	}
+	return 0, false, false, false
+}
+
+// PortfolioOutcome is a portfolio rule evaluation together with the
+// conditions alerted on now
+type PortfolioOutcome struct {
+	*PortfolioResult
+	New    []Condition // Conditions to notify about now
+	Queued []Condition // Conditions held back for the quiet hours digest
+}
+
+// IsNew reports whether the condition is alerted on in this evaluation
+func (o PortfolioOutcome) IsNew(c Condition) bool {
+	return containsCondition(o.New, c)
+}
+
+// EvaluatePortfolio evaluates every enabled portfolio rule and advances their
+// alert state. Nothing is evaluated without any valued position, so a failed
+// refresh does not re-arm alerts. Outcomes are valid even when an error
+// persisting the state is returned.
+func (t *Tracker) EvaluatePortfolio(e *Evaluator, rules []config.PortfolioRule, p *Portfolio, now time.Time) ([]PortfolioOutcome, error) {
+	var outcomes []PortfolioOutcome
+	// Totals missing a holding would look like a drop, so wait for every quote
+	if len(p.Positions) == 0 || len(p.Missing) > 0 {
+		return nil, nil
+	}
+	err := t.update(func(s *State) bool {
+		outcomes = outcomes[:0]
+		changed := false
+		for i := range rules {
+			r := &rules[i]
+			if !r.IsEnabled() {
+				continue
+			}
+			o := PortfolioOutcome{PortfolioResult: e.EvaluatePortfolio(r, p)}
+			var alerted bool
+			o.New, alerted = advance(s.Alerts, r.ID, r.AlertPolicy, o.Conditions, func(c Condition) (float64, bool, bool, bool) {
+				return observePortfolio(c, p)
+			}, s.silence(r.ID, now) != nil, now)
+			o.New, o.Queued = t.hold(s, o.Subject(), nil, o.New, now)
+			changed = changed || alerted
+			outcomes = append(outcomes, o)
+		}
+		return changed
+	})
+	return outcomes, err
+}
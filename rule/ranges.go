@@ -0,0 +1,113 @@
+package rule
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// gapPercent returns the opening gap vs the previous close in percent, NaN if unknown
+func gapPercent(q *stock.Quote) float64 {
+	if q.Open <= 0 || q.PrevClose <= 0 {
+		return math.NaN()
+	}
+	return (q.Open - q.PrevClose) / q.PrevClose * 100
+}
+
+// completedBars returns how many daily candles closed before the quote's day
+func completedBars(c *stock.Candle, q *stock.Quote) int {
+	if c == nil {
+		return 0
+	}
+	n := min(len(c.T), len(c.H), len(c.L))
+	day := quoteTime(q).UTC().Format("2006-01-02")
+	if n > 0 && time.Unix(c.T[n-1], 0).UTC().Format("2006-01-02") == day {
+		n--
+	}
+	return n
+}
+
+// rangeHighLow returns the highest high and lowest low of the last days
+// completed bars, false if there are not enough bars
+func rangeHighLow(c *stock.Candle, q *stock.Quote, days int) (high, low float64, ok bool) {
+	n := completedBars(c, q)
+	if days < 1 || n < days {
+		return 0, 0, false
+	}
+	high, low = c.H[n-days], c.L[n-days]
+	for i := n - days + 1; i < n; i++ {
+		high = max(high, c.H[i])
+		low = min(low, c.L[i])
+	}
+	return high, low, true
+}
+
+// evaluateRanges adds gap, new intraday high/low, previous-day break and
+// N-day breakout conditions
+func evaluateRanges(result *TriggerResult, r *config.Rule, in Input) {
+	q := in.Quote
+	if q.CurrentPrice <= 0 {
+		return
+	}
+
+	// Opening gap vs the previous close
+	if gap := gapPercent(q); !math.IsNaN(gap) {
+		if r.GapAbove != nil && gap > *r.GapAbove {
+			result.add(CondGapUp, *r.GapAbove, gap,
+				i18n.T("Gap up %.2f%%, more than %.2f%% (open $%.2f, previous close $%.2f)", gap, *r.GapAbove, q.Open, q.PrevClose))
+		}
+		if r.GapBelow != nil && gap < *r.GapBelow {
+			result.add(CondGapDown, *r.GapBelow, gap,
+				i18n.T("Gap down %.2f%%, more than %.2f%% (open $%.2f, previous close $%.2f)", gap, *r.GapBelow, q.Open, q.PrevClose))
+		}
+	}
+
+	// New intraday high/low, ignoring the noisy first minutes of the session
+	if r.NewDayHighAfter != nil || r.NewDayLowAfter != nil {
+		now := quoteTime(q)
+		elapsed := now.Sub(stock.SessionOpen(in.Market, now))
+		if after := r.NewDayHighAfter; after != nil && elapsed >= time.Duration(*after) &&
+			q.High > 0 && q.CurrentPrice >= q.High {
+			result.add(CondNewDayHigh, time.Duration(*after).Minutes(), q.CurrentPrice,
+				i18n.T("New intraday high $%.2f (%s after the open)", q.CurrentPrice, after))
+		}
+		if after := r.NewDayLowAfter; after != nil && elapsed >= time.Duration(*after) &&
+			q.Low > 0 && q.CurrentPrice <= q.Low {
+			result.add(CondNewDayLow, time.Duration(*after).Minutes(), q.CurrentPrice,
+				i18n.T("New intraday low $%.2f (%s after the open)", q.CurrentPrice, after))
+		}
+	}
+
+	// Previous day's high/low. The clause keeps the condition stable as the level
+	// moves from day to day.
+	if r.BreakPrevHigh || r.BreakPrevLow {
+		if high, low, ok := rangeHighLow(in.Candles, q, 1); ok {
+			if r.BreakPrevHigh && q.CurrentPrice > high {
+				result.addClause(CondBreakPrevHigh, "prev_high", high, q.CurrentPrice,
+					i18n.T("Price $%.2f broke above the previous high $%.2f", q.CurrentPrice, high))
+			}
+			if r.BreakPrevLow && q.CurrentPrice < low {
+				result.addClause(CondBreakPrevLow, "prev_low", low, q.CurrentPrice,
+					i18n.T("Price $%.2f broke below the previous low $%.2f", q.CurrentPrice, low))
+			}
+		}
+	}
+
+	// N-day high/low from completed daily candles
+	if r.NewHighDays > 0 {
+		if high, _, ok := rangeHighLow(in.Candles, q, r.NewHighDays); ok && q.CurrentPrice > high {
+			result.addClause(CondNewHighDays, fmt.Sprintf("%dd", r.NewHighDays), high, q.CurrentPrice,
+				i18n.T("Price $%.2f at a %d-day high (previous high $%.2f)", q.CurrentPrice, r.NewHighDays, high))
+		}
+	}
+	if r.NewLowDays > 0 {
+		if _, low, ok := rangeHighLow(in.Candles, q, r.NewLowDays); ok && q.CurrentPrice < low {
+			result.addClause(CondNewLowDays, fmt.Sprintf("%dd", r.NewLowDays), low, q.CurrentPrice,
+				i18n.T("Price $%.2f at a %d-day low (previous low $%.2f)", q.CurrentPrice, r.NewLowDays, low))
+		}
+	}
+}
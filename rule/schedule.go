@@ -0,0 +1,17 @@
+package rule
+
+import (
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// isActive reports whether now is within the rule's active schedule, read in
+// the timezone of the symbol's market
+func isActive(r *config.Rule, market string, now time.Time) bool {
+	if r.Active == nil {
+		return true
+	}
+	return r.Active.Contains(now.In(stock.Location(market)), stock.SessionOpen(market, now))
+}
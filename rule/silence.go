@@ -0,0 +1,103 @@
+package rule
+
+import (
+	"slices"
+	"time"
+)
+
+// Silence holds back the alerts of a rule, set from the dashboard. A silenced
+// rule is still evaluated, but its conditions stay armed, so whatever is still
+// met when the silence ends alerts right away.
+type Silence struct {
+	Until time.Time `json:"until,omitzero"` // Snoozed until then, zero if disabled until resumed
+	At    time.Time `json:"at"`             // When the rule was silenced
+}
+
+// Disabled reports whether the rule was disabled rather than snoozed
+func (s *Silence) Disabled() bool {
+	return s.Until.IsZero()
+}
+
+// Active reports whether the silence still holds at now. A nil silence never does.
+func (s *Silence) Active(now time.Time) bool {
+	return s != nil && (s.Until.IsZero() || now.Before(s.Until))
+}
+
+// silence returns the active silence of rule id, or nil
+func (s *State) silence(id string, now time.Time) *Silence {
+	if sil := s.Silences[id]; sil.Active(now) {
+		return sil
+	}
+	return nil
+}
+
+// Acknowledge marks the fired conditions of the rules as seen, so they send no
+// repeat_every reminders until they re-arm. It returns how many conditions
+// were acknowledged.
+func (t *Tracker) Acknowledge(ids []string) (int, error) {
+	acked := 0
+	err := t.update(func(s *State) bool {
+		acked = 0
+		for _, st := range s.Alerts {
+			if st.Fired && !st.Acked && slices.Contains(ids, st.Condition.RuleID) {
+				st.Acked = true
+				acked++
+			}
+		}
+		return acked > 0
+	})
+	return acked, err
+}
+
+// Snooze holds back the alerts of the rules until the given time. Their fired
+// conditions are re-armed, so those still met then alert again.
+func (t *Tracker) Snooze(ids []string, until, now time.Time) error {
+	return t.silence(ids, &Silence{Until: until, At: now}, true)
+}
+
+// Disable holds back the alerts of the rules until they are resumed
+func (t *Tracker) Disable(ids []string, now time.Time) error {
+	return t.silence(ids, &Silence{At: now}, false)
+}
+
+func (t *Tracker) silence(ids []string, sil *Silence, rearm bool) error {
+	return t.update(func(s *State) bool {
+		if s.Silences == nil {
+			s.Silences = make(map[string]*Silence)
+		}
+		for _, id := range ids {
+			s.Silences[id] = sil
+		}
+		if rearm {
+			for _, st := range s.Alerts {
+				if st.Fired && slices.Contains(ids, st.Condition.RuleID) {
+					st.Fired, st.Acked = false, false
+				}
+			}
+		}
+		return len(ids) > 0
+	})
+}
+
+// Resume lifts the snooze or disable and the acknowledgements of the rules.
+// It returns how many rules were silenced or acknowledged.
+func (t *Tracker) Resume(ids []string) (int, error) {
+	var resumed map[string]bool
+	err := t.update(func(s *State) bool {
+		resumed = make(map[string]bool)
+		for _, id := range ids {
+			if _, ok := s.Silences[id]; ok {
+				delete(s.Silences, id)
+				resumed[id] = true
+			}
+		}
+		for _, st := range s.Alerts {
+			if st.Acked && slices.Contains(ids, st.Condition.RuleID) {
+				st.Acked = false
+				resumed[st.Condition.RuleID] = true
+			}
+		}
+		return len(resumed) > 0
+	})
+	return len(resumed), err
+}
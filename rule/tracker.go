@@ -0,0 +1,439 @@
+package rule
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+// AlertState is the persisted state of one rule condition
+type AlertState struct {
+	Condition Condition `json:"condition"`       // Condition as last seen while met
+	Fired     bool      `json:"fired"`           // Fired and not yet re-armed
+	Acked     bool      `json:"acked,omitempty"` // Acknowledged, no reminders until re-armed
+	LastFired time.Time `json:"last_fired,omitzero"`
+	LastReset time.Time `json:"last_reset,omitzero"`
+}
+
+// Peak is the persisted high-water mark of a rule's trailing stop
+type Peak struct {
+	Price  float64   `json:"price"`
+	At     time.Time `json:"at"`
+	Since  string    `json:"since,omitempty"`  // Anchor date the peak is tracked from
+	Seeded bool      `json:"seeded,omitempty"` // Daily highs since the anchor were included
+}
+
+// QueuedAlert is an alert held back during quiet hours or for a digest
+type QueuedAlert struct {
+	Subject   string       `json:"subject"` // Symbol or portfolio rule name
+	Condition Condition    `json:"condition"`
+	At        time.Time    `json:"at"`
+	Quote     *stock.Quote `json:"quote,omitempty"` // Quote the alert was raised on, nil for portfolio rules
+}
+
+// QueueAlerts returns the alerts for the conditions of subject raised now
+func QueueAlerts(subject string, quote *stock.Quote, conditions []Condition, now time.Time) []QueuedAlert {
+	alerts := make([]QueuedAlert, len(conditions))
+	for i, c := range conditions {
+		alerts[i] = QueuedAlert{Subject: subject, Condition: c, At: now, Quote: quote}
+	}
+	return alerts
+}
+
+// State is the alert state persisted by a StateStore
+type State struct {
+	Alerts map[string]*AlertState `json:"alerts"`           // Keyed by Condition.Key
+	Peaks  map[string]*Peak       `json:"peaks,omitempty"`  // Keyed by rule ID
+	Digest []QueuedAlert          `json:"digest,omitempty"` // Alerts held back during quiet hours
+
+	Silences map[string]*Silence `json:"silences,omitempty"` // Snoozed or disabled rules, keyed by rule ID
+}
+
+// NewState creates an empty state
+func NewState() *State {
+	return &State{
+		Alerts:   make(map[string]*AlertState),
+		Peaks:    make(map[string]*Peak),
+		Silences: make(map[string]*Silence),
+	}
+}
+
+// StateStore persists alert state so it survives restarts and can be shared
+// by several processes
+type StateStore interface {
+	// Update calls fn with the stored state while holding an exclusive lock,
+	// and saves it if fn reports a change
+	Update(fn func(s *State) (changed bool)) error
+}
+
+// Tracker is the alert-state machine shared by watch and the dashboard. Each
+// condition of each rule moves between two states:
+//
+//   - armed: the next time the condition is met it fires, unless the rule's
+//     cooldown since the last alert for it has not elapsed yet
+//   - fired: it does not fire again, except as a repeat_every reminder, until
+//     it is no longer met and, with a rearm_band, the value has moved back past
+//     the threshold by the band
+//
+// It also keeps the high-water mark of trailing stops, skips rules outside
+// their active schedule, and queues alerts raised during quiet hours.
+type Tracker struct {
+	store StateStore         // nil keeps state in memory only
+	state *State             // Last known state, used if the store fails
+	quiet *config.QuietHours // nil sends every alert immediately
+}
+
+// NewTracker creates a tracker backed by store, or kept in memory if store is nil
+func NewTracker(store StateStore) *Tracker {
+	return &Tracker{store: store, state: NewState()}
+}
+
+// SetQuietHours sets the window in which alerts are queued for a digest
+func (t *Tracker) SetQuietHours(q *config.QuietHours) {
+	t.quiet = q
+}
+
+// Evaluate evaluates a rule, advances its alert state and returns the outcome.
+// A rule outside its active schedule is not evaluated and its state is left
+// as is. If the state cannot be persisted the in-memory state is used and the
+// error is returned alongside a valid outcome.
+func (t *Tracker) Evaluate(e *Evaluator, r *config.Rule, in Input, now time.Time) (Outcome, error) {
+	var o Outcome
+	if !isActive(r, in.Market, now) {
+		o.TriggerResult = &TriggerResult{Rule: r, Quote: in.Quote, Holding: in.Holding}
+		o.Inactive = true
+		return o, nil
+	}
+	err := t.update(func(s *State) bool {
+		changed := false
+		if r.HasTrailingStop() {
+			in.Peak, changed = updatePeak(s, r, in, now)
+		}
+		o.TriggerResult = e.Evaluate(r, in)
+		o.Silence = s.silence(r.ID, now)
+		var alerted bool
+		o.New, alerted = advance(s.Alerts, r.ID, r.AlertPolicy, o.Conditions, func(c Condition) (float64, bool, bool, bool) {
+			return observe(r, c, in)
+		}, o.Silence != nil, now)
+		o.New, o.Queued = t.hold(s, r.Symbol, in.Quote, o.New, now)
+		o.Acked = acked(s.Alerts, o.Conditions)
+		return changed || alerted
+	})
+	return o, err
+}
+
+// hold queues alerts raised during quiet hours, returning the alerts to send
+// now and the ones queued
+func (t *Tracker) hold(s *State, subject string, quote *stock.Quote, alerts []Condition, now time.Time) (send, queued []Condition) {
+	if t.quiet == nil || !t.quiet.Contains(now) {
+		return alerts, nil
+	}
+	s.Digest = append(s.Digest, QueueAlerts(subject, quote, alerts, now)...)
+	return nil, alerts
+}
+
+// FlushDigest returns and clears the alerts queued during quiet hours once
+// they are over. It returns nothing while quiet hours are still on. A digest
+// that fails to send goes back with RequeueDigest.
+func (t *Tracker) FlushDigest(now time.Time) ([]QueuedAlert, error) {
+	if t.quiet != nil && t.quiet.Contains(now) {
+		return nil, nil
+	}
+	var digest []QueuedAlert
+	err := t.update(func(s *State) bool {
+		digest, s.Digest = s.Digest, nil
+		return len(digest) > 0
+	})
+	return digest, err
+}
+
+// RequeueDigest puts back a digest returned by FlushDigest that could not be
+// sent, ahead of anything queued since, so it is sent on the next flush
+func (t *Tracker) RequeueDigest(digest []QueuedAlert) error {
+	if len(digest) == 0 {
+		return nil
+	}
+	return t.update(func(s *State) bool {
+		s.Digest = append(slices.Clone(digest), s.Digest...)
+		return true
+	})
+}
+
+// FormatDigest returns one notification for alerts queued during quiet hours
+func FormatDigest(digest []QueuedAlert) (title, body string) {
+	title = i18n.T("🌙 %d alerts during quiet hours", len(digest))
+	for _, a := range digest {
+		body += fmt.Sprintf("%s [%s] %s: %s\n", a.At.Local().Format("01-02 15:04"), a.Condition.RuleID, a.Subject, a.Condition.Text)
+	}
+	return title, body
+}
+
+// DigestSeverity returns the highest severity of the alerts in a digest
+func DigestSeverity(digest []QueuedAlert) config.Severity {
+	conditions := make([]Condition, len(digest))
+	for i, a := range digest {
+		conditions[i] = a.Condition
+	}
+	return HighestSeverity(conditions)
+}
+
+// update runs step on the stored state, falling back to the in-memory state
+// if the store cannot be read
+func (t *Tracker) update(step func(s *State) bool) error {
+	if t.store == nil {
+		step(t.state)
+		return nil
+	}
+
+	ran := false
+	err := t.store.Update(func(s *State) bool {
+		ran = true
+		t.state = s
+		return step(s)
+	})
+	if err != nil && !ran {
+		step(t.state)
+	}
+	return err
+}
+
+// updatePeak raises the rule's high-water mark to the current price. A new
+// peak, or one whose anchor date changed, is seeded from the daily highs
+// since the anchor, retried on later updates while candles are missing.
+func updatePeak(s *State, r *config.Rule, in Input, now time.Time) (float64, bool) {
+	anchor := trailingAnchor(r, in.Holding)
+	since := ""
+	if !anchor.IsZero() {
+		since = anchor.Format(config.DateLayout)
+	}
+
+	changed := false
+	p := s.Peaks[r.ID]
+	if p == nil || p.Since != since {
+		p = &Peak{Since: since}
+		s.Peaks[r.ID] = p
+		changed = true
+	}
+	if c := in.Candles; c != nil && since != "" && !p.Seeded {
+		for i := range c.H {
+			// Daily bars are stamped in UTC, so compare by date rather than instant
+			if i < len(c.T) && time.Unix(c.T[i], 0).UTC().Format(config.DateLayout) >= since && c.H[i] > p.Price {
+				p.Price, p.At = c.H[i], time.Unix(c.T[i], 0)
+			}
+		}
+		p.Seeded = true
+		changed = true
+	}
+
+	if q := in.Quote; q != nil && q.CurrentPrice > p.Price {
+		p.Price, p.At = q.CurrentPrice, now
+		changed = true
+	}
+	return p.Price, changed
+}
+
+// advance runs the state machine for the conditions of rule id over states.
+// While silenced, met conditions stay armed and send no reminders.
+func advance(states map[string]*AlertState, id string, policy config.AlertPolicy, conditions []Condition, observe observer, silenced bool, now time.Time) (alerts []Condition, changed bool) {
+	met := make(map[string]bool, len(conditions))
+	for _, c := range conditions {
+		key := c.Key()
+		if met[key] {
+			continue
+		}
+		met[key] = true
+
+		st := states[key]
+		if st == nil {
+			st = &AlertState{}
+			states[key] = st
+			changed = true
+		}
+		st.Condition = c
+
+		switch {
+		case silenced:
+			continue
+		case !st.Fired:
+			if !st.LastFired.IsZero() && now.Sub(st.LastFired) < time.Duration(policy.Cooldown) {
+				continue // Still cooling down, stay armed
+			}
+			st.Fired = true
+		case !st.Acked && policy.RepeatEvery > 0 && now.Sub(st.LastFired) >= time.Duration(policy.RepeatEvery):
+			// Reminder while the condition stays met
+		default:
+			continue
+		}
+		st.LastFired = now
+		changed = true
+		alerts = append(alerts, c)
+	}
+
+	// Re-arm fired conditions of this rule that are no longer met
+	for key, st := range states {
+		if st.Condition.RuleID != id || met[key] || !st.Fired {
+			continue
+		}
+		if rearmed(policy.RearmBand, st.Condition, observe) {
+			st.Fired, st.Acked = false, false
+			st.LastReset = now
+			changed = true
+		}
+	}
+	return alerts, changed
+}
+
+// acked returns the conditions that were acknowledged while fired
+func acked(states map[string]*AlertState, conditions []Condition) []Condition {
+	var acked []Condition
+	for _, c := range conditions {
+		if st := states[c.Key()]; st != nil && st.Fired && st.Acked {
+			acked = append(acked, c)
+		}
+	}
+	return acked
+}
+
+// rearmed reports whether a condition that is no longer met has moved far
+// enough back past its threshold to fire again
+func rearmed(band *config.Band, c Condition, observe observer) bool {
+	if band == nil {
+		return true
+	}
+	v, above, percent, ok := observe(c)
+	if !ok || math.IsNaN(v) {
+		return true // No single value to measure, re-arm as soon as it clears
+	}
+	distance := band.Of(c.Threshold, percent)
+	if above {
+		return v <= c.Threshold-distance
+	}
+	return v >= c.Threshold+distance
+}
+
+// observer returns the current value a threshold condition compares, whether it
+// fires above (rather than below) the threshold, and whether the value is a percentage
+type observer func(c Condition) (v float64, above, percent, ok bool)
+
+// observe is the observer of symbol rule conditions
+func observe(r *config.Rule, c Condition, in Input) (v float64, above, percent, ok bool) {
+	q := in.Quote
+	if q == nil {
+		return 0, false, false, false
+	}
+	switch c.Type {
+	case CondPriceAbove, CondLimitUp:
+		return q.CurrentPrice, true, false, true
+	case CondPriceBelow, CondLimitDown:
+		return q.CurrentPrice, false, false, true
+	case CondChangeAbove:
+		return q.PercentChange, true, true, true
+	case CondChangeBelow:
+		return q.PercentChange, false, true, true
+	case CondNearLimitUp:
+		if q.CurrentPrice <= 0 || !q.HasLimits() {
+			return 0, false, false, false
+		}
+		return (q.LimitUp - q.CurrentPrice) / q.CurrentPrice * 100, false, true, true
+	case CondNearLimitDown:
+		if q.CurrentPrice <= 0 || !q.HasLimits() {
+			return 0, false, false, false
+		}
+		return (q.CurrentPrice - q.LimitDown) / q.CurrentPrice * 100, false, true, true
+	case CondGainAbove:
+		return positionGain(q, in.Holding), true, true, true
+	case CondLossBelow:
+		return positionGain(q, in.Holding), false, true, true
+	case CondPLAbove:
+		return positionPL(q, in.Holding), true, false, true
+	case CondPLBelow:
+		return positionPL(q, in.Holding), false, false, true
+	case CondGapUp:
+		return gapPercent(q), true, true, true
+	case CondGapDown:
+		return gapPercent(q), false, true, true
+	case CondBreakPrevHigh, CondNewHighDays:
+		return q.CurrentPrice, true, false, true
+	case CondBreakPrevLow, CondNewLowDays:
+		return q.CurrentPrice, false, false, true
+	case CondPeriodChangeAbove, CondPeriodChangeBelow:
+		v, ok := observeChange(r, c, in)
+		return v, c.Type == CondPeriodChangeAbove, true, ok
+	case CondPairAbove, CondPairBelow:
+		if r.Pair == nil {
+			return 0, false, false, false
+		}
+		return currentSpread(r.Pair, in), c.Type == CondPairAbove, r.Pair.GetFormula() == config.PairSpreadPct, true
+	case CondPairZScore:
+		if r.Pair == nil {
+			return 0, false, false, false
+		}
+		z, _, _, ok := pairZScore(r.Pair, in)
+		return math.Abs(z), true, false, ok
+	case CondTrailingStopPct:
+		if in.Peak <= 0 {
+			return 0, false, false, false
+		}
+		return (in.Peak - q.CurrentPrice) / in.Peak * 100, true, true, true
+	case CondTrailingStopAmount:
+		if in.Peak <= 0 {
+			return 0, false, false, false
+		}
+		return in.Peak - q.CurrentPrice, true, false, true
+	}
+	return 0, false, false, false
+}
+
+// Outcome is a rule evaluation together with the conditions alerted on now
+type Outcome struct {
+	*TriggerResult
+	New      []Condition // Conditions to notify about now
+	Queued   []Condition // Conditions held back for the quiet hours digest
+	Acked    []Condition // Conditions acknowledged from the dashboard
+	Silence  *Silence    // Set while the rule is snoozed or disabled
+	Inactive bool        // The rule is outside its active schedule
+}
+
+// IsNew reports whether the condition is alerted on in this evaluation
+func (o Outcome) IsNew(c Condition) bool {
+	return containsCondition(o.New, c)
+}
+
+// IsAcked reports whether the condition was acknowledged from the dashboard
+func (o Outcome) IsAcked(c Condition) bool {
+	return containsCondition(o.Acked, c)
+}
+
+// containsCondition reports whether conditions has one with the key of c
+func containsCondition(conditions []Condition, c Condition) bool {
+	for _, n := range conditions {
+		if n.Key() == c.Key() {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateAll evaluates every enabled rule against the same input and advances
+// the alert state, returning one outcome per enabled rule. Outcomes are valid
+// even when an error persisting the state is returned.
+func (t *Tracker) EvaluateAll(e *Evaluator, rules []*config.Rule, in Input, now time.Time) ([]Outcome, error) {
+	var outcomes []Outcome
+	var firstErr error
+	for _, r := range rules {
+		if !r.IsEnabled() {
+			continue
+		}
+		o, err := t.Evaluate(e, r, in, now)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		outcomes = append(outcomes, o)
+	}
+	return outcomes, firstErr
+}
@@ -0,0 +1,346 @@
+package stock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	binanceRESTURL = "https://api.binance.com/api/v3"
+	binanceWSURL   = "wss://stream.binance.com:9443/ws"
+
+	// A streamed ticker older than this is considered stale and we fall back to REST
+	binanceStaleAfter = 30 * time.Second
+)
+
+// binanceSymbol maps a Yahoo-style crypto symbol (BTC-USD) to a Binance pair (BTCUSDT).
+// USD is mapped to USDT since Binance has no USD spot pairs.
+func binanceSymbol(symbol string) string {
+	s := strings.ToUpper(strings.TrimSpace(symbol))
+	base, quote, ok := strings.Cut(s, "-")
+	if !ok {
+		return s // Already a Binance pair, e.g. BTCUSDT
+	}
+	if quote == "USD" {
+		quote = "USDT"
+	}
+	return base + quote
+}
+
+// binanceInterval maps a Finnhub-style resolution to a Binance kline interval
+func binanceInterval(resolution string) string {
+	switch resolution {
+	case "1", "5", "15", "30":
+		return resolution + "m"
+	case "60":
+		return "1h"
+	case "240":
+		return "4h"
+	case "W":
+		return "1w"
+	case "M":
+		return "1M"
+	default:
+		return "1d"
+	}
+}
+
+// binanceTicker is the REST 24hr ticker response
+type binanceTicker struct {
+	Symbol             string `json:"symbol"`
+	PriceChange        string `json:"priceChange"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	LastPrice          string `json:"lastPrice"`
+	OpenPrice          string `json:"openPrice"`
+	HighPrice          string `json:"highPrice"`
+	LowPrice           string `json:"lowPrice"`
+	Volume             string `json:"volume"`
+	CloseTime          int64  `json:"closeTime"`
+}
+
+// binanceStreamTicker is the WebSocket <symbol>@ticker event
+type binanceStreamTicker struct {
+	Event              string `json:"e"`
+	EventTime          int64  `json:"E"`
+	Symbol             string `json:"s"`
+	PriceChange        string `json:"p"`
+	PriceChangePercent string `json:"P"`
+	LastPrice          string `json:"c"`
+	OpenPrice          string `json:"o"`
+	HighPrice          string `json:"h"`
+	LowPrice           string `json:"l"`
+	Volume             string `json:"v"`
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// newBinanceQuote builds a Quote from 24h rolling window values.
+// PrevClose is the price 24 hours ago so PercentChange is the real 24h change.
+func newBinanceQuote(symbol, last, change, percent, open, high, low, volume string, ts int64) *Quote {
+	price := parseFloat(last)
+	chg := parseFloat(change)
+	return &Quote{
+		Symbol:        symbol,
+		CurrentPrice:  price,
+		Change:        chg,
+		PercentChange: parseFloat(percent),
+		High:          parseFloat(high),
+		Low:           parseFloat(low),
+		Open:          parseFloat(open),
+		PrevClose:     price - chg,
+		Volume:        parseFloat(volume),
+		Timestamp:     ts / 1000,
+	}
+}
+
+// BinanceProvider serves crypto quotes and candles from Binance.
+// Quotes come from a WebSocket ticker stream when available, otherwise from REST.
+type BinanceProvider struct {
+	httpClient *http.Client
+	stream     *binanceStream
+}
+
+// NewBinanceProvider creates a new Binance provider
+func NewBinanceProvider() *BinanceProvider {
+	return &BinanceProvider{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		stream:     newBinanceStream(),
+	}
+}
+
+// GetQuote returns the latest streamed ticker for symbol, fetching via REST if
+// the stream has no fresh data yet. Every requested symbol is subscribed to the stream.
+func (p *BinanceProvider) GetQuote(symbol string) (*Quote, error) {
+	pair := binanceSymbol(symbol)
+	p.stream.subscribe(pair)
+
+	if q := p.stream.latest(pair); q != nil {
+		quote := *q
+		quote.Symbol = symbol
+		return &quote, nil
+	}
+
+	return p.fetchTicker(symbol, pair)
+}
+
+func (p *BinanceProvider) fetchTicker(symbol, pair string) (*Quote, error) {
+	reqURL := fmt.Sprintf("%s/ticker/24hr?symbol=%s", binanceRESTURL, url.QueryEscape(pair))
+
+	resp, err := p.httpClient.Get(reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch quote from Binance: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Binance API status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var t binanceTicker
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, fmt.Errorf("failed to decode Binance response: %w", err)
+	}
+
+	return newBinanceQuote(symbol, t.LastPrice, t.PriceChange, t.PriceChangePercent,
+		t.OpenPrice, t.HighPrice, t.LowPrice, t.Volume, t.CloseTime), nil
+}
+
+// GetCandles fetches klines from Binance
+func (p *BinanceProvider) GetCandles(symbol string, resolution string, from, to int64) (*Candle, error) {
+	reqURL := fmt.Sprintf("%s/klines?symbol=%s&interval=%s&startTime=%d&endTime=%d&limit=1000",
+		binanceRESTURL, url.QueryEscape(binanceSymbol(symbol)), binanceInterval(resolution), from*1000, to*1000)
+
+	resp, err := p.httpClient.Get(reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch from Binance: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Binance API status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Each kline is [openTime, open, high, low, close, volume, closeTime, ...]
+	var klines [][]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&klines); err != nil {
+		return nil, fmt.Errorf("failed to decode Binance response: %w", err)
+	}
+
+	if len(klines) == 0 {
+		return &Candle{S: "no_data"}, nil
+	}
+
+	candle := &Candle{S: "ok"}
+	for _, k := range klines {
+		if len(k) < 6 {
+			continue
+		}
+		var openTime int64
+		var o, h, l, c, v string
+		json.Unmarshal(k[0], &openTime)
+		json.Unmarshal(k[1], &o)
+		json.Unmarshal(k[2], &h)
+		json.Unmarshal(k[3], &l)
+		json.Unmarshal(k[4], &c)
+		json.Unmarshal(k[5], &v)
+
+		candle.T = append(candle.T, openTime/1000)
+		candle.O = append(candle.O, parseFloat(o))
+		candle.H = append(candle.H, parseFloat(h))
+		candle.L = append(candle.L, parseFloat(l))
+		candle.C = append(candle.C, parseFloat(c))
+		candle.V = append(candle.V, parseFloat(v))
+	}
+
+	return candle, nil
+}
+
+// binanceStream maintains a single WebSocket connection subscribed to the
+// 24h ticker of every requested pair, reconnecting with backoff on failure.
+type binanceStream struct {
+	mu         sync.RWMutex
+	tickers    map[string]*Quote
+	updated    map[string]time.Time
+	subscribed map[string]bool
+	conn       *websocket.Conn
+	started    bool
+	nextID     int
+}
+
+func newBinanceStream() *binanceStream {
+	return &binanceStream{
+		tickers:    make(map[string]*Quote),
+		updated:    make(map[string]time.Time),
+		subscribed: make(map[string]bool),
+	}
+}
+
+// latest returns the streamed quote for pair if it is fresh
+func (s *binanceStream) latest(pair string) *Quote {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q, ok := s.tickers[pair]
+	if !ok || time.Since(s.updated[pair]) > binanceStaleAfter {
+		return nil
+	}
+	return q
+}
+
+// subscribe adds pair to the stream, starting the connection on first use
+func (s *binanceStream) subscribe(pair string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subscribed[pair] {
+		return
+	}
+	s.subscribed[pair] = true
+
+	if !s.started {
+		s.started = true
+		go s.run()
+		return
+	}
+
+	if s.conn != nil {
+		s.sendSubscribe([]string{pair})
+	}
+}
+
+// sendSubscribe must be called with s.mu held
+func (s *binanceStream) sendSubscribe(pairs []string) {
+	params := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		params = append(params, strings.ToLower(p)+"@ticker")
+	}
+	s.nextID++
+	req := map[string]interface{}{
+		"method": "SUBSCRIBE",
+		"params": params,
+		"id":     s.nextID,
+	}
+	if err := s.conn.WriteJSON(req); err != nil {
+		log.Printf("Binance stream subscribe failed: %v", err)
+	}
+}
+
+func (s *binanceStream) run() {
+	backoff := time.Second
+	for {
+		start := time.Now()
+		if err := s.connectAndRead(); err != nil {
+			log.Printf("Binance stream disconnected: %v", err)
+		}
+
+		// Reset backoff if the connection was healthy for a while
+		if time.Since(start) > time.Minute {
+			backoff = time.Second
+		}
+
+		time.Sleep(backoff)
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+func (s *binanceStream) connectAndRead() error {
+	conn, _, err := websocket.DefaultDialer.Dial(binanceWSURL, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	s.mu.Lock()
+	s.conn = conn
+	pairs := make([]string, 0, len(s.subscribed))
+	for p := range s.subscribed {
+		pairs = append(pairs, p)
+	}
+	s.sendSubscribe(pairs)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+	}()
+
+	for {
+		// Binance pings every 3 minutes; the default handler answers with a pong
+		conn.SetReadDeadline(time.Now().Add(10 * time.Minute))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var t binanceStreamTicker
+		if err := json.Unmarshal(data, &t); err != nil || t.Event != "24hrTicker" {
+			continue // Subscription acks and other messages
+		}
+
+		q := newBinanceQuote(t.Symbol, t.LastPrice, t.PriceChange, t.PriceChangePercent,
+			t.OpenPrice, t.HighPrice, t.LowPrice, t.Volume, t.EventTime)
+
+		s.mu.Lock()
+		s.tickers[t.Symbol] = q
+		s.updated[t.Symbol] = time.Now()
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,93 @@
+package stock
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CandleCache caches candles per symbol and resolution so rules can use
+// indicators without refetching history on every refresh cycle
+type CandleCache struct {
+	client *Client
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]candleCacheEntry
+}
+
+type candleCacheEntry struct {
+	candles *Candle
+	bars    int
+	fetched time.Time
+}
+
+// NewCandleCache creates a candle cache that refetches after ttl
+func NewCandleCache(client *Client, ttl time.Duration) *CandleCache {
+	return &CandleCache{
+		client:  client,
+		ttl:     ttl,
+		entries: make(map[string]candleCacheEntry),
+	}
+}
+
+// Daily returns at least bars daily candles for symbol
+func (c *CandleCache) Daily(symbol, market string, bars int) (*Candle, error) {
+	return c.Get(symbol, market, "D", bars)
+}
+
+// Get returns at least bars candles of the given resolution for symbol.
+// Intraday candles are refetched at least once per bar.
+func (c *CandleCache) Get(symbol, market, resolution string, bars int) (*Candle, error) {
+	key := symbol + "|" + resolution
+	ttl := c.ttl
+	if minutes, err := strconv.Atoi(resolution); err == nil {
+		ttl = min(ttl, time.Duration(minutes)*time.Minute)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && entry.bars >= bars && time.Since(entry.fetched) < ttl {
+		return entry.candles, nil
+	}
+
+	to := time.Now()
+	from := to.Add(-candleSpan(resolution, bars))
+
+	candles, err := c.client.GetCandles(symbol, market, resolution, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = candleCacheEntry{candles: candles, bars: bars, fetched: time.Now()}
+	c.mu.Unlock()
+
+	return candles, nil
+}
+
+// candleSpan returns how far back to fetch to cover bars candles, including
+// weekends, holidays and the hours markets are closed
+func candleSpan(resolution string, bars int) time.Duration {
+	const day = 24 * time.Hour
+	switch resolution {
+	case "W":
+		return time.Duration(bars*7+14) * day
+	case "M":
+		return time.Duration(bars*31+62) * day
+	}
+	minutes, err := strconv.Atoi(resolution)
+	if err != nil {
+		return time.Duration(bars*3/2+10) * day // Daily
+	}
+	// A session is about a quarter of the clock on five days of seven
+	span := time.Duration(bars*minutes)*time.Minute*6 + 4*day
+	// Yahoo serves intraday bars for 60 days at most (7 for 1-minute bars)
+	limit := 59 * day
+	if minutes == 1 {
+		limit = 7 * day
+	}
+	return min(span, limit)
+}
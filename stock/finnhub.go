@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/congregalis/stock-ping/i18n"
 )
 
 // Quote represents stock quote data from Finnhub
@@ -17,7 +19,15 @@ type Quote struct {
 	Low           float64 // l - Low price of the day
 	Open          float64 // o - Open price of the day
 	PrevClose     float64 // pc - Previous close price
+	Volume        float64 // Volume (24h for crypto, not provided by Finnhub)
 	Timestamp     int64   // t - Timestamp
+	LimitUp       float64 // Limit-up price (涨停价), A-shares only
+	LimitDown     float64 // Limit-down price (跌停价), A-shares only
+}
+
+// HasLimits returns true if the quote carries price-limit bands
+func (q *Quote) HasLimits() bool {
+	return q.LimitUp > 0 && q.LimitDown > 0
 }
 
 // finnhubResponse is the raw API response structure
@@ -43,31 +53,58 @@ type Candle struct {
 	V []float64 `json:"v"` // List of volume data
 }
 
-// Client is a Finnhub API client
+// Client is a Finnhub API client that routes other markets to native providers
 type Client struct {
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
+
+	// Native providers per market; markets without one use Yahoo
+	quoteProviders  map[string]QuoteProvider
+	candleProviders map[string]CandleProvider
 }
 
 // NewClient creates a new Finnhub client
 func NewClient(apiKey string) *Client {
+	binance := NewBinanceProvider()
+	tencent := NewTencentProvider()
 	return &Client{
 		apiKey:  apiKey,
 		baseURL: "https://finnhub.io/api/v1",
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		quoteProviders: map[string]QuoteProvider{
+			MarketCrypto: binance,
+			MarketCN:     tencent,
+		},
+		candleProviders: map[string]CandleProvider{
+			MarketCrypto: binance,
+		},
 	}
 }
 
 // GetQuote fetches the current quote for a symbol
 func (c *Client) GetQuote(symbol string, market string) (*Quote, error) {
-	// Use Finnhub for US market, Yahoo for others
+	// Use Finnhub for US market, native providers or Yahoo for others
 	if market == MarketUS || market == "" {
 		return c.getFinnhubQuote(symbol)
 	}
-	return FetchYahooQuote(symbol)
+
+	p, ok := c.quoteProviders[market]
+	if !ok {
+		return FetchYahooQuote(symbol)
+	}
+
+	quote, err := p.GetQuote(symbol)
+	if err != nil {
+		// Fall back to Yahoo if the native provider is unavailable
+		if yq, yerr := FetchYahooQuote(symbol); yerr == nil {
+			return yq, nil
+		}
+		return nil, err
+	}
+	return quote, nil
 }
 
 func (c *Client) getFinnhubQuote(symbol string) (*Quote, error) {
@@ -107,11 +144,15 @@ func (c *Client) getFinnhubQuote(symbol string) (*Quote, error) {
 }
 
 // GetCandles fetches historical candle data
-// NOTE: Uses Yahoo Finance (Free) instead of Finnhub (Paid)
-func (c *Client) GetCandles(symbol string, resolution string, from, to int64) (*Candle, error) {
-	// We ignore resolution (hardcoded to 1d in Yahoo for now as per requirement)
-	// and API key since we use free Yahoo API.
-	return FetchYahooCandles(symbol, from, to)
+// NOTE: Uses native providers or Yahoo Finance (Free) instead of Finnhub (Paid)
+func (c *Client) GetCandles(symbol string, market string, resolution string, from, to int64) (*Candle, error) {
+	if p, ok := c.candleProviders[market]; ok {
+		candles, err := p.GetCandles(symbol, resolution, from, to)
+		if err == nil {
+			return candles, nil
+		}
+	}
+	return yahooProvider{}.GetCandles(symbol, resolution, from, to)
 }
 
 // FormatQuote returns a formatted string representation of the quote
@@ -126,12 +167,15 @@ func (q *Quote) FormatQuote(name string) string {
 		changeSign = "+"
 	}
 
-	return fmt.Sprintf(`📈 %s
-   价格: $%.2f
-   涨跌: %s$%.2f (%s%.2f%%)
-   今日: $%.2f ~ $%.2f`,
+	s := i18n.T("📈 %s\n   Price: $%.2f\n   Change: %s$%.2f (%+.2f%%)\n   Today: $%.2f ~ $%.2f",
 		displayName,
 		q.CurrentPrice,
-		changeSign, q.Change, changeSign, q.PercentChange,
+		changeSign, q.Change, q.PercentChange,
 		q.Low, q.High)
+
+	if q.HasLimits() {
+		s += i18n.T("\n   Limit up/down: ¥%.2f / ¥%.2f", q.LimitUp, q.LimitDown)
+	}
+
+	return s
 }
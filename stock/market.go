@@ -33,27 +33,46 @@ func init() {
 	}
 }
 
+// Location returns the timezone a market's trading hours are given in
+func Location(market string) *time.Location {
+	switch market {
+	case MarketCrypto:
+		return time.UTC
+	case MarketCN, MarketHK, MarketTW:
+		return tzShanghai // Hong Kong and Taipei share UTC+8 with Shanghai
+	default:
+		return tzEastern
+	}
+}
+
 // IsMarketOpen checks if a specific market is currently open
 func IsMarketOpen(market string) bool {
+	return IsMarketOpenAt(market, time.Now())
+}
+
+// IsMarketOpenAt checks if a specific market is open at t
+func IsMarketOpenAt(market string, t time.Time) bool {
 	switch market {
 	case MarketCrypto:
 		return true // Crypto is 24/7
 	case MarketCN:
-		return isCNMarketOpen()
+		return isCNMarketOpen(t)
+	case MarketHK:
+		return isHKMarketOpen(t)
 	case MarketTW:
-		return isTWMarketOpen()
+		return isTWMarketOpen(t)
 	case MarketForex:
-		return isForexOpen()
+		return isForexOpen(t)
 	case MarketUS:
-		return isUSMarketOpen()
+		return isUSMarketOpen(t)
 	default:
 		// Default to US if not specified or unknown
-		return isUSMarketOpen()
+		return isUSMarketOpen(t)
 	}
 }
 
-func isUSMarketOpen() bool {
-	now := time.Now().In(tzEastern)
+func isUSMarketOpen(t time.Time) bool {
+	now := t.In(tzEastern)
 	weekday := now.Weekday()
 
 	// Weekends
@@ -68,29 +87,63 @@ func isUSMarketOpen() bool {
 	return timeInMinutes >= 9*60+30 && timeInMinutes < 16*60
 }
 
-func isCNMarketOpen() bool {
-	now := time.Now().In(tzShanghai)
+func isCNMarketOpen(t time.Time) bool {
+	_, open := currentSegment(MarketCN, t)
+	return open
+}
+
+func isHKMarketOpen(t time.Time) bool {
+	// Hong Kong shares timezone with Shanghai (HKT/GMT+8)
+	_, open := currentSegment(MarketHK, t)
+	return open
+}
+
+// sessionSegments are the trading segments of markets with a lunch break, in
+// minutes after midnight local time
+var sessionSegments = map[string][][2]int{
+	// Morning: 09:30 - 11:30
+	// Afternoon: 13:00 - 15:00
+	MarketCN: {{9*60 + 30, 11*60 + 30}, {13 * 60, 15 * 60}},
+	// Morning: 09:30 - 12:00
+	// Afternoon: 13:00 - 16:00
+	MarketHK: {{9*60 + 30, 12 * 60}, {13 * 60, 16 * 60}},
+}
+
+// currentSegment returns when the trading segment containing t started, and
+// false if the market is not trading at t
+func currentSegment(market string, t time.Time) (time.Time, bool) {
+	now := t.In(Location(market))
 	weekday := now.Weekday()
 
 	// Weekends
 	if weekday == time.Saturday || weekday == time.Sunday {
-		return false
+		return time.Time{}, false
 	}
 
 	hour, min, _ := now.Clock()
 	timeInMinutes := hour*60 + min
+	for _, seg := range sessionSegments[market] {
+		if timeInMinutes >= seg[0] && timeInMinutes < seg[1] {
+			start := time.Date(now.Year(), now.Month(), now.Day(), 0, seg[0], 0, 0, now.Location())
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
 
-	// Morning: 09:30 - 11:30
-	// Afternoon: 13:00 - 15:00
-	isMorning := timeInMinutes >= 9*60+30 && timeInMinutes < 11*60+30
-	isAfternoon := timeInMinutes >= 13*60 && timeInMinutes < 15*60
-
-	return isMorning || isAfternoon
+// SegmentOpen returns when trading resumed in the session containing t: the
+// end of the lunch break for markets that have one and are in their
+// afternoon segment, otherwise SessionOpen
+func SegmentOpen(market string, t time.Time) time.Time {
+	if start, ok := currentSegment(market, t); ok {
+		return start
+	}
+	return SessionOpen(market, t)
 }
 
-func isTWMarketOpen() bool {
+func isTWMarketOpen(t time.Time) bool {
 	// Taiwan shares timezone with Shanghai (CST/GMT+8)
-	now := time.Now().In(tzShanghai)
+	now := t.In(tzShanghai)
 	weekday := now.Weekday()
 
 	// Weekends
@@ -105,10 +158,10 @@ func isTWMarketOpen() bool {
 	return timeInMinutes >= 9*60 && timeInMinutes < 13*60+30
 }
 
-func isForexOpen() bool {
+func isForexOpen(t time.Time) bool {
 	// Forex/Metals typically trade 24/5
 	// Opens Sunday 17:00 EST, Closes Friday 17:00 EST
-	now := time.Now().In(tzEastern)
+	now := t.In(tzEastern)
 	weekday := now.Weekday()
 
 	if weekday == time.Saturday {
@@ -130,6 +183,60 @@ func isForexOpen() bool {
 	return true
 }
 
+// SessionOpen returns when the trading session containing t opened, or the most
+// recent session before t if the market is closed
+func SessionOpen(market string, t time.Time) time.Time {
+	tz := Location(market)
+	hour, min := 9, 30
+	switch market {
+	case MarketCrypto:
+		// Daily bars roll over at midnight UTC
+		t = t.UTC()
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case MarketForex:
+		// Sessions run from 17:00 ET to 17:00 ET the next day
+		hour, min = 17, 0
+	case MarketTW:
+		hour, min = 9, 0
+	}
+
+	local := t.In(tz)
+	open := time.Date(local.Year(), local.Month(), local.Day(), hour, min, 0, 0, tz)
+	if open.After(t) {
+		open = open.AddDate(0, 0, -1)
+	}
+	if market == MarketForex {
+		return open
+	}
+	// Step back over weekends to the last weekday session
+	for open.Weekday() == time.Saturday || open.Weekday() == time.Sunday {
+		open = open.AddDate(0, 0, -1)
+	}
+	return open
+}
+
+// NextSessionOpen returns when the trading session after the one containing t
+// opens, or the next session if the market is closed at t
+func NextSessionOpen(market string, t time.Time) time.Time {
+	open := SessionOpen(market, t).AddDate(0, 0, 1)
+	for !open.After(t) || !isSessionDay(market, open) {
+		open = open.AddDate(0, 0, 1)
+	}
+	return open
+}
+
+// isSessionDay reports whether a session opens on the day of open
+func isSessionDay(market string, open time.Time) bool {
+	switch market {
+	case MarketCrypto:
+		return true
+	case MarketForex:
+		// Sessions open Sunday to Thursday evening
+		return open.Weekday() != time.Friday && open.Weekday() != time.Saturday
+	}
+	return open.Weekday() != time.Saturday && open.Weekday() != time.Sunday
+}
+
 // GetNextMarketOpen returns the next opening time for the given market
 // This is used for UI countdowns (optional)
 func GetNextMarketOpen(market string) time.Time {
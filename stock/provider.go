@@ -0,0 +1,23 @@
+package stock
+
+// QuoteProvider fetches real-time quotes for a symbol
+type QuoteProvider interface {
+	GetQuote(symbol string) (*Quote, error)
+}
+
+// CandleProvider fetches historical candles for a symbol.
+// Resolution uses Finnhub notation: "1", "5", "15", "30", "60" (minutes), "D", "W", "M".
+type CandleProvider interface {
+	GetCandles(symbol string, resolution string, from, to int64) (*Candle, error)
+}
+
+// yahooProvider is the fallback provider for every market
+type yahooProvider struct{}
+
+func (yahooProvider) GetQuote(symbol string) (*Quote, error) {
+	return FetchYahooQuote(symbol)
+}
+
+func (yahooProvider) GetCandles(symbol string, resolution string, from, to int64) (*Candle, error) {
+	return FetchYahooCandles(symbol, resolution, from, to)
+}
@@ -0,0 +1,134 @@
+package stock
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const tencentQuoteURL = "https://qt.gtimg.cn/q="
+
+// Field indexes in the Tencent quote text format:
+// v_sh600519="1~贵州茅台~600519~1466.00~1470.06~1470.00~28539~...";
+const (
+	tcFieldPrice     = 3
+	tcFieldPrevClose = 4
+	tcFieldOpen      = 5
+	tcFieldVolume    = 6 // In lots (手) of 100 shares
+	tcFieldTime      = 30
+	tcFieldChange    = 31
+	tcFieldPercent   = 32
+	tcFieldHigh      = 33
+	tcFieldLow       = 34
+	tcFieldLimitUp   = 47 // 涨停价
+	tcFieldLimitDown = 48 // 跌停价
+)
+
+// tencentSymbol maps a Yahoo-style A-share symbol (600519.SS) to a Tencent code (sh600519)
+func tencentSymbol(symbol string) (string, error) {
+	code, suffix, ok := strings.Cut(strings.ToUpper(symbol), ".")
+	if !ok {
+		return "", fmt.Errorf("unsupported A-share symbol: %s", symbol)
+	}
+	switch suffix {
+	case "SS", "SH":
+		return "sh" + code, nil
+	case "SZ":
+		return "sz" + code, nil
+	case "BJ":
+		return "bj" + code, nil
+	default:
+		return "", fmt.Errorf("unsupported A-share symbol: %s", symbol)
+	}
+}
+
+// TencentProvider serves real-time China A-share quotes from the Tencent quote feed
+type TencentProvider struct {
+	httpClient *http.Client
+}
+
+// NewTencentProvider creates a new Tencent provider
+func NewTencentProvider() *TencentProvider {
+	return &TencentProvider{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GetQuote fetches the real-time quote including limit-up/limit-down bands
+func (p *TencentProvider) GetQuote(symbol string) (*Quote, error) {
+	code, err := tencentSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Get(tencentQuoteURL + code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch quote from Tencent: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Tencent API status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Tencent response: %w", err)
+	}
+
+	return parseTencentQuote(symbol, string(body))
+}
+
+// parseTencentQuote parses a single v_xxx="...~...~"; line.
+// The name field is GBK encoded but we only read numeric fields.
+func parseTencentQuote(symbol, body string) (*Quote, error) {
+	start := strings.IndexByte(body, '"')
+	end := strings.LastIndexByte(body, '"')
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("no data found for %s", symbol)
+	}
+
+	fields := strings.Split(body[start+1:end], "~")
+	if len(fields) <= tcFieldLimitDown {
+		return nil, fmt.Errorf("no data found for %s", symbol)
+	}
+
+	num := func(i int) float64 {
+		f, _ := strconv.ParseFloat(fields[i], 64)
+		return f
+	}
+
+	price := num(tcFieldPrice)
+	prevClose := num(tcFieldPrevClose)
+	if price == 0 && prevClose == 0 {
+		return nil, fmt.Errorf("no data available for symbol: %s", symbol)
+	}
+
+	// Suspended stocks report 0 as the current price
+	if price == 0 {
+		price = prevClose
+	}
+
+	var timestamp int64
+	if t, err := time.ParseInLocation("20060102150405", fields[tcFieldTime], tzShanghai); err == nil {
+		timestamp = t.Unix()
+	}
+
+	return &Quote{
+		Symbol:        symbol,
+		CurrentPrice:  price,
+		Change:        num(tcFieldChange),
+		PercentChange: num(tcFieldPercent),
+		High:          num(tcFieldHigh),
+		Low:           num(tcFieldLow),
+		Open:          num(tcFieldOpen),
+		PrevClose:     prevClose,
+		Volume:        num(tcFieldVolume) * 100,
+		Timestamp:     timestamp,
+		LimitUp:       num(tcFieldLimitUp),
+		LimitDown:     num(tcFieldLimitDown),
+	}, nil
+}
@@ -100,6 +100,7 @@ func FetchYahooQuote(symbol string) (*Quote, error) {
 		Low:           low,
 		Open:          open,
 		PrevClose:     prevClose,
+		Volume:        meta.RegularMarketVolume,
 		Timestamp:     int64(meta.RegularMarketTime),
 	}, nil
 }
@@ -121,6 +122,7 @@ type YahooChartResponse struct {
 				ExchangeTimezoneName string  `json:"exchangeTimezoneName"`
 				RegularMarketPrice   float64 `json:"regularMarketPrice"`
 				ChartPreviousClose   float64 `json:"chartPreviousClose"`
+				RegularMarketVolume  float64 `json:"regularMarketVolume"`
 				PriceHint            int     `json:"priceHint"`
 			} `json:"meta"`
 			Timestamp  []int64 `json:"timestamp"`
@@ -141,11 +143,26 @@ type YahooChartResponse struct {
 	} `json:"chart"`
 }
 
-// FetchYahooCandles fetches historical data from Yahoo Finance
-func FetchYahooCandles(symbol string, period1, period2 int64) (*Candle, error) {
+// yahooInterval maps a Finnhub-style resolution to a Yahoo chart interval
+func yahooInterval(resolution string) string {
+	switch resolution {
+	case "1", "5", "15", "30", "60":
+		return resolution + "m"
+	case "W":
+		return "1wk"
+	case "M":
+		return "1mo"
+	default:
+		return "1d"
+	}
+}
+
+// FetchYahooCandles fetches historical data from Yahoo Finance.
+// Yahoo keeps 1-minute bars for 7 days and other intraday bars for 60 days.
+func FetchYahooCandles(symbol string, resolution string, period1, period2 int64) (*Candle, error) {
 	// Yahoo uses seconds for timestamps
-	url := fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=1d",
-		symbol, period1, period2)
+	url := fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=%s",
+		symbol, period1, period2, yahooInterval(resolution))
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
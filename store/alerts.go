@@ -0,0 +1,236 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/rule"
+)
+
+// Alert log segments are named by UTC month
+const alertSegmentLayout = "2006-01"
+
+// Delivery results of an alert
+const (
+	DeliverySent   = "sent"
+	DeliveryFailed = "failed"
+	DeliveryNone   = "none" // No notification channel is configured
+)
+
+// How an alert reached its channels, besides on its own
+const (
+	ViaDigest     = "digest"      // Batched into a digest notification
+	ViaQuietHours = "quiet_hours" // Queued during quiet hours and sent after them
+)
+
+// AlertRecord is a fired alert in the alert log
+type AlertRecord struct {
+	Time      time.Time       `json:"time"`    // When the alert fired
+	Subject   string          `json:"subject"` // Symbol or portfolio rule name
+	RuleID    string          `json:"rule_id"`
+	Type      string          `json:"type"`
+	Severity  config.Severity `json:"severity"`
+	Text      string          `json:"text"`
+	Threshold float64         `json:"threshold,omitempty"`
+	Value     float64         `json:"value,omitempty"`
+	Quote     *Tick           `json:"quote,omitempty"`    // Quote the alert fired on, nil for portfolio rules
+	Channels  []string        `json:"channels,omitempty"` // Channels the alert was sent to
+	Delivery  string          `json:"delivery"`           // One of the Delivery* constants
+	Via       string          `json:"via,omitempty"`      // One of the Via* constants, empty if sent on its own
+	Error     string          `json:"error,omitempty"`    // Why the delivery failed
+}
+
+// NewAlertRecords returns the records of alerts delivered together through
+// channels, err being the result of the delivery
+func NewAlertRecords(alerts []rule.QueuedAlert, via string, channels []string, err error) []AlertRecord {
+	delivery := DeliverySent
+	var errText string
+	switch {
+	case len(channels) == 0:
+		delivery = DeliveryNone
+	case err != nil:
+		delivery = DeliveryFailed
+		errText = err.Error()
+	}
+
+	records := make([]AlertRecord, len(alerts))
+	for i, a := range alerts {
+		severity := a.Condition.Severity
+		if severity == "" {
+			severity = config.SeverityInfo
+		}
+		records[i] = AlertRecord{
+			Time:      a.At,
+			Subject:   a.Subject,
+			RuleID:    a.Condition.RuleID,
+			Type:      a.Condition.Type,
+			Severity:  severity,
+			Text:      a.Condition.Text,
+			Threshold: a.Condition.Threshold,
+			Value:     a.Condition.Value,
+			Channels:  channels,
+			Delivery:  delivery,
+			Via:       via,
+			Error:     errText,
+		}
+		if a.Quote != nil {
+			tick := newTick(a.Quote, a.At)
+			records[i].Quote = &tick
+		}
+	}
+	return records
+}
+
+// AlertFilter selects records from the alert log. Zero fields match everything.
+type AlertFilter struct {
+	Subject     string // Symbol or portfolio rule name, case-insensitive
+	RuleID      string
+	From, To    time.Time
+	MinSeverity config.Severity
+}
+
+// Matches reports whether r is selected by the filter
+func (f AlertFilter) Matches(r AlertRecord) bool {
+	if f.Subject != "" && !strings.EqualFold(r.Subject, f.Subject) {
+		return false
+	}
+	if f.RuleID != "" && r.RuleID != f.RuleID {
+		return false
+	}
+	if !f.From.IsZero() && r.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && r.Time.After(f.To) {
+		return false
+	}
+	return r.Severity.AtLeast(f.MinSeverity)
+}
+
+// AlertLog is an append-only history of every fired alert and how it was delivered.
+// Layout: <dir>/alerts/<YYYY-MM>.jsonl
+type AlertLog struct {
+	dir string
+}
+
+// NewAlertLog creates an alert log in the configured data directory
+func NewAlertLog(cfg config.HistoryConfig) (*AlertLog, error) {
+	dir := filepath.Join(cfg.DataDir(), "alerts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create alert log: %w", err)
+	}
+	return &AlertLog{dir: dir}, nil
+}
+
+// Append records alerts delivered together, in the segment of the month the
+// first one fired
+func (l *AlertLog) Append(records []AlertRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("failed to encode alert: %w", err)
+		}
+	}
+
+	// A single O_APPEND write keeps lines intact when watch and dashboard run together
+	path := filepath.Join(l.dir, records[0].Time.UTC().Format(alertSegmentLayout)+segmentExt)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open alert log: %w", err)
+	}
+	_, err = f.Write(buf.Bytes())
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write alert: %w", err)
+	}
+	return nil
+}
+
+// Query returns the records selected by filter, oldest first
+func (l *AlertLog) Query(filter AlertFilter) ([]AlertRecord, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+
+	var records []AlertRecord
+	for _, e := range entries {
+		month, err := time.Parse(alertSegmentLayout, strings.TrimSuffix(e.Name(), segmentExt))
+		if err != nil {
+			continue
+		}
+		// A segment may hold alerts delivered up to a day into the next month
+		if !filter.From.IsZero() && month.AddDate(0, 1, 1).Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && month.After(filter.To) {
+			continue
+		}
+
+		segRecords, err := readAlertSegment(filepath.Join(l.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range segRecords {
+			if filter.Matches(r) {
+				records = append(records, r)
+			}
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
+func readAlertSegment(path string) ([]AlertRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open alert log: %w", err)
+	}
+	defer f.Close()
+
+	var records []AlertRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r AlertRecord
+		// Skip partial lines from an interrupted write
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// DeliveryText describes how the alert was delivered, e.g. "sent (digest)"
+func (r AlertRecord) DeliveryText() string {
+	var text string
+	switch r.Delivery {
+	case DeliverySent:
+		text = i18n.T("sent")
+	case DeliveryFailed:
+		text = i18n.T("failed")
+	default:
+		text = i18n.T("not sent")
+	}
+	switch r.Via {
+	case ViaDigest:
+		text += i18n.T(" (digest)")
+	case ViaQuietHours:
+		text += i18n.T(" (quiet hours)")
+	}
+	return text
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/internal/filelock"
+	"github.com/congregalis/stock-ping/rule"
+)
+
+const (
+	stateFileName = "alert-state.json"
+
+	// Armed conditions idle for this long are dropped
+	statePruneAfter = 30 * 24 * time.Hour
+)
+
+// StateStore keeps alert state in <dir>/alert-state.json. Every access takes
+// an exclusive file lock, so watch and dashboard can run side by side.
+type StateStore struct {
+	path     string
+	lockPath string
+
+	// State of rules no longer in the config file is dropped. The file is
+	// re-read when it changes, since another process may have added a rule.
+	configPath string
+	mu         sync.Mutex
+	configMod  time.Time
+	ruleIDs    map[string]bool
+}
+
+// NewStateStore creates a state store in the configured data directory,
+// keeping state for the rules of the config file at configPath
+func NewStateStore(cfg config.HistoryConfig, configPath string) (*StateStore, error) {
+	dir := cfg.DataDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	path := filepath.Join(dir, stateFileName)
+	return &StateStore{path: path, lockPath: path + ".lock", configPath: configPath}, nil
+}
+
+// Path returns the state file path
+func (s *StateStore) Path() string {
+	return s.path
+}
+
+// Update implements rule.StateStore: it loads the state file under the lock,
+// runs fn and saves the result if fn reports a change
+func (s *StateStore) Update(fn func(st *rule.State) bool) error {
+	lock, err := filelock.Acquire(s.lockPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	f, err := s.load()
+	if err != nil {
+		return err
+	}
+	if !fn(f) {
+		return nil
+	}
+	prune(f, s.currentRuleIDs(), time.Now())
+	return s.save(f)
+}
+
+// Load returns a snapshot of the stored state
+func (s *StateStore) Load() (*rule.State, error) {
+	var snapshot *rule.State
+	err := s.Update(func(st *rule.State) bool {
+		snapshot = st
+		return false
+	})
+	return snapshot, err
+}
+
+func (s *StateStore) load() (*rule.State, error) {
+	f := rule.NewState()
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read alert state: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, f); err != nil {
+			return nil, fmt.Errorf("failed to parse alert state %s: %w", s.path, err)
+		}
+	}
+	if f.Alerts == nil {
+		f.Alerts = make(map[string]*rule.AlertState)
+	}
+	if f.Peaks == nil {
+		f.Peaks = make(map[string]*rule.Peak)
+	}
+	if f.Silences == nil {
+		f.Silences = make(map[string]*rule.Silence)
+	}
+	return f, nil
+}
+
+// save writes the state to a temporary file and renames it into place, so a
+// crash never leaves a truncated file behind
+func (s *StateStore) save(f *rule.State) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode alert state: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write alert state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write alert state: %w", err)
+	}
+	return nil
+}
+
+// currentRuleIDs returns the rule IDs of the config file, or nil if it cannot
+// be read, in which case no state is dropped for its rule
+func (s *StateStore) currentRuleIDs() map[string]bool {
+	if s.configPath == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := os.Stat(s.configPath)
+	if err != nil {
+		return nil
+	}
+	if !info.ModTime().Equal(s.configMod) {
+		ids, err := config.RuleIDs(s.configPath)
+		if err != nil {
+			return nil
+		}
+		s.ruleIDs, s.configMod = ids, info.ModTime()
+	}
+	return s.ruleIDs
+}
+
+// prune drops armed conditions that have been idle for statePruneAfter,
+// snoozes that are over and all state of rules not in ruleIDs (unless nil)
+func prune(f *rule.State, ruleIDs map[string]bool, now time.Time) {
+	removed := func(id string) bool {
+		return ruleIDs != nil && !ruleIDs[id] && id != rule.FeedRuleID
+	}
+	for id, sil := range f.Silences {
+		if !sil.Active(now) || removed(id) {
+			delete(f.Silences, id)
+		}
+	}
+	for id := range f.Peaks {
+		if removed(id) {
+			delete(f.Peaks, id)
+		}
+	}
+	for key, st := range f.Alerts {
+		last := st.LastFired
+		if st.LastReset.After(last) {
+			last = st.LastReset
+		}
+		if !st.Fired && now.Sub(last) > statePruneAfter || removed(st.Condition.RuleID) {
+			delete(f.Alerts, key)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/internal/filelock"
+	"github.com/congregalis/stock-ping/stock"
+)
+
+const (
+	// Segments are named by UTC date; compacted segments get a ".c" marker
+	segmentDateLayout = "2006-01-02"
+	segmentExt        = ".jsonl"
+	compactedExt      = ".c.jsonl"
+
+	// How often retention and compaction run while appending
+	maintainEvery = 6 * time.Hour
+)
+
+// Tick is a single recorded quote
+type Tick struct {
+	Time          int64   `json:"t"`            // Unix seconds when the quote was fetched
+	QuoteTime     int64   `json:"qt,omitempty"` // Quote timestamp reported by the provider
+	Price         float64 `json:"p"`
+	Change        float64 `json:"d"`
+	PercentChange float64 `json:"dp"`
+	Open          float64 `json:"o,omitempty"`
+	High          float64 `json:"h,omitempty"`
+	Low           float64 `json:"l,omitempty"`
+	PrevClose     float64 `json:"pc,omitempty"`
+	Volume        float64 `json:"v,omitempty"`
+}
+
+// Quote converts the tick back into a stock.Quote
+func (t Tick) Quote(symbol string) *stock.Quote {
+	return &stock.Quote{
+		Symbol:        symbol,
+		CurrentPrice:  t.Price,
+		Change:        t.Change,
+		PercentChange: t.PercentChange,
+		High:          t.High,
+		Low:           t.Low,
+		Open:          t.Open,
+		PrevClose:     t.PrevClose,
+		Volume:        t.Volume,
+		Timestamp:     t.QuoteTime,
+	}
+}
+
+// newTick returns the tick for a quote fetched at now
+func newTick(q *stock.Quote, now time.Time) Tick {
+	return Tick{
+		Time:          now.Unix(),
+		QuoteTime:     q.Timestamp,
+		Price:         q.CurrentPrice,
+		Change:        q.Change,
+		PercentChange: q.PercentChange,
+		Open:          q.Open,
+		High:          q.High,
+		Low:           q.Low,
+		PrevClose:     q.PrevClose,
+		Volume:        q.Volume,
+	}
+}
+
+// TickStore is an append-only, per-symbol, day-segmented quote history.
+// Layout: <dir>/ticks/<SYMBOL>/<YYYY-MM-DD>.jsonl
+type TickStore struct {
+	dir             string
+	lockPath        string // Held while maintaining, as watch and dashboard may both do it
+	retention       time.Duration
+	compactAfter    time.Duration
+	compactInterval int64
+
+	mu           sync.Mutex
+	lastMaintain time.Time
+	maintainErr  error // Error of the last background maintenance, until reported
+}
+
+// NewTickStore creates a tick store from the history configuration
+func NewTickStore(cfg config.HistoryConfig) (*TickStore, error) {
+	dir := filepath.Join(cfg.DataDir(), "ticks")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tick store: %w", err)
+	}
+
+	interval := int64(cfg.CompactInterval)
+	if interval <= 0 {
+		interval = 60
+	}
+
+	return &TickStore{
+		dir:             dir,
+		lockPath:        filepath.Join(cfg.DataDir(), "ticks.lock"),
+		retention:       time.Duration(cfg.RetentionDays) * 24 * time.Hour,
+		compactAfter:    time.Duration(cfg.CompactAfterDays) * 24 * time.Hour,
+		compactInterval: interval,
+	}, nil
+}
+
+func (s *TickStore) symbolDir(symbol string) string {
+	return filepath.Join(s.dir, url.PathEscape(symbol))
+}
+
+// Append records a quote fetched now
+func (s *TickStore) Append(q *stock.Quote) error {
+	now := time.Now()
+	line, err := json.Marshal(newTick(q, now))
+	if err != nil {
+		return fmt.Errorf("failed to encode tick: %w", err)
+	}
+	line = append(line, '\n')
+
+	dir := s.symbolDir(q.Symbol)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create tick dir: %w", err)
+	}
+
+	// A single O_APPEND write keeps lines intact when watch and dashboard run together
+	path := filepath.Join(dir, now.UTC().Format(segmentDateLayout)+segmentExt)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open tick segment: %w", err)
+	}
+	_, err = f.Write(line)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write tick: %w", err)
+	}
+
+	s.mu.Lock()
+	due := time.Since(s.lastMaintain) > maintainEvery
+	if due {
+		s.lastMaintain = now
+	}
+	s.mu.Unlock()
+
+	if due {
+		go func() {
+			err := s.Maintain()
+			s.mu.Lock()
+			s.maintainErr = err
+			s.mu.Unlock()
+		}()
+	}
+
+	return nil
+}
+
+// MaintainErr returns and clears the error of the last background
+// maintenance started by Append, nil if it succeeded
+func (s *TickStore) MaintainErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.maintainErr
+	s.maintainErr = nil
+	return err
+}
+
+// segment is a single day file for a symbol
+type segment struct {
+	path      string
+	day       time.Time
+	compacted bool
+}
+
+func (s *TickStore) segments(symbol string) ([]segment, error) {
+	entries, err := os.ReadDir(s.symbolDir(symbol))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var segs []segment
+	for _, e := range entries {
+		name := e.Name()
+		compacted := strings.HasSuffix(name, compactedExt)
+		base := strings.TrimSuffix(strings.TrimSuffix(name, compactedExt), segmentExt)
+		day, err := time.Parse(segmentDateLayout, base)
+		if err != nil {
+			continue
+		}
+		segs = append(segs, segment{
+			path:      filepath.Join(s.symbolDir(symbol), name),
+			day:       day,
+			compacted: compacted,
+		})
+	}
+
+	sort.Slice(segs, func(i, j int) bool { return segs[i].day.Before(segs[j].day) })
+	return segs, nil
+}
+
+// Query returns the ticks recorded for symbol within [from, to], oldest first
+func (s *TickStore) Query(symbol string, from, to time.Time) ([]Tick, error) {
+	segs, err := s.segments(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ticks: %w", err)
+	}
+
+	fromDay := from.UTC().Truncate(24 * time.Hour)
+	var ticks []Tick
+	for _, seg := range segs {
+		if seg.day.Before(fromDay) || seg.day.After(to.UTC()) {
+			continue
+		}
+		segTicks, err := readSegment(seg.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range segTicks {
+			if t.Time >= from.Unix() && t.Time <= to.Unix() {
+				ticks = append(ticks, t)
+			}
+		}
+	}
+
+	sort.SliceStable(ticks, func(i, j int) bool { return ticks[i].Time < ticks[j].Time })
+	return ticks, nil
+}
+
+// Latest returns the most recent tick for symbol, or nil if none was recorded
+func (s *TickStore) Latest(symbol string) (*Tick, error) {
+	segs, err := s.segments(symbol)
+	if err != nil || len(segs) == 0 {
+		return nil, err
+	}
+	ticks, err := readSegment(segs[len(segs)-1].path)
+	if err != nil || len(ticks) == 0 {
+		return nil, err
+	}
+	return &ticks[len(ticks)-1], nil
+}
+
+// Daily returns up to bars daily candles built from the quotes recorded for
+// symbol, for when the provider has no candle history. Bars are stamped at UTC
+// midnight of the market's trading day, as provider daily bars are.
+func (s *TickStore) Daily(symbol, market string, bars int) (*stock.Candle, error) {
+	// Calendar days over-fetch
+	now := time.Now()
+	ticks, err := s.Query(symbol, now.AddDate(0, 0, -2*bars-7), now)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := stock.Location(market)
+	c := &stock.Candle{S: "ok"}
+	lastDay := ""
+	for _, t := range ticks {
+		if t.Price <= 0 {
+			continue
+		}
+		at := time.Unix(t.Time, 0)
+		if t.QuoteTime > 0 {
+			at = time.Unix(t.QuoteTime, 0)
+		}
+		y, m, d := at.In(loc).Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+		// Quotes carry the session's open, high and low so far
+		open, high, low := t.Open, max(t.High, t.Price), t.Low
+		if open <= 0 {
+			open = t.Price
+		}
+		if low <= 0 || t.Price < low {
+			low = t.Price
+		}
+
+		if key := day.Format(config.DateLayout); key != lastDay {
+			lastDay = key
+			c.T = append(c.T, day.Unix())
+			c.O = append(c.O, open)
+			c.H = append(c.H, high)
+			c.L = append(c.L, low)
+			c.C = append(c.C, t.Price)
+			c.V = append(c.V, t.Volume)
+			continue
+		}
+		i := len(c.T) - 1
+		c.H[i] = max(c.H[i], high)
+		c.L[i] = min(c.L[i], low)
+		c.C[i] = t.Price
+		c.V[i] = max(c.V[i], t.Volume)
+	}
+	if len(c.T) == 0 {
+		return nil, fmt.Errorf("no quotes recorded for %s", symbol)
+	}
+
+	if n := len(c.T) - bars; n > 0 {
+		c.T, c.O, c.H, c.L, c.C, c.V = c.T[n:], c.O[n:], c.H[n:], c.L[n:], c.C[n:], c.V[n:]
+	}
+	return c, nil
+}
+
+// Symbols returns every symbol with recorded history
+func (s *TickStore) Symbols() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var symbols []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if sym, err := url.PathUnescape(e.Name()); err == nil {
+			symbols = append(symbols, sym)
+		}
+	}
+	return symbols, nil
+}
+
+func readSegment(path string) ([]Tick, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tick segment: %w", err)
+	}
+	defer f.Close()
+
+	var ticks []Tick
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var t Tick
+		// Skip partial lines from an interrupted write
+		if err := json.Unmarshal(scanner.Bytes(), &t); err != nil {
+			continue
+		}
+		ticks = append(ticks, t)
+	}
+	return ticks, scanner.Err()
+}
+
+// Maintain applies retention and compaction to every symbol
+func (s *TickStore) Maintain() error {
+	lock, err := filelock.Acquire(s.lockPath)
+	if err != nil {
+		return fmt.Errorf("failed to maintain tick history: %w", err)
+	}
+	defer lock.Release()
+
+	symbols, err := s.Symbols()
+	if err != nil {
+		return fmt.Errorf("failed to maintain tick history: %w", err)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, symbol := range symbols {
+		segs, err := s.segments(symbol)
+		if err != nil {
+			return fmt.Errorf("failed to maintain tick history: %w", err)
+		}
+		for _, seg := range segs {
+			age := today.Sub(seg.day)
+
+			if s.retention > 0 && age > s.retention {
+				os.Remove(seg.path)
+				continue
+			}
+
+			// Never compact today's segment, it is still being appended to
+			if s.compactAfter > 0 && age >= s.compactAfter && age > 0 && !seg.compacted {
+				if err := s.compact(seg); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// compact downsamples a segment to at most one tick per compaction interval,
+// keeping the last tick of each interval
+func (s *TickStore) compact(seg segment) error {
+	ticks, err := readSegment(seg.path)
+	if err != nil {
+		return err
+	}
+
+	var kept []Tick
+	for i, t := range ticks {
+		bucket := t.Time / s.compactInterval
+		if i+1 < len(ticks) && ticks[i+1].Time/s.compactInterval == bucket {
+			continue
+		}
+		kept = append(kept, t)
+	}
+
+	outPath := strings.TrimSuffix(seg.path, segmentExt) + compactedExt
+	tmpPath := outPath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to compact ticks: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, t := range kept {
+		if err := enc.Encode(t); err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to compact ticks: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to compact ticks: %w", err)
+	}
+	f.Close()
+
+	if err := os.Rename(tmpPath, outPath); err != nil {
+		return fmt.Errorf("failed to compact ticks: %w", err)
+	}
+	return os.Remove(seg.path)
+}
@@ -1,6 +1,10 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/congregalis/stock-ping/i18n"
+)
 
 // keyMap defines keybindings
 type keyMap struct {
@@ -14,52 +18,90 @@ type keyMap struct {
 	Back      key.Binding // Esc, Backspace
 	Portfolio key.Binding // p - switch to portfolio view
 	Dashboard key.Binding // d - switch to dashboard view
+	Alerts    key.Binding // a - switch to alert history view
 	Privacy   key.Binding // P - toggle privacy/share mode
+
+	// Alerts of the selected stock
+	Acknowledge   key.Binding // A - acknowledge the alerts met
+	Snooze        key.Binding // z - snooze the rules alerting
+	SnoozeSession key.Binding // Z - snooze the rules alerting until the next session
+	Disable       key.Binding // X - disable the rules alerting until resumed
+	Resume        key.Binding // u - lift snoozes, disables and acknowledgements
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Refresh, k.Sort, k.Portfolio, k.Dashboard, k.Privacy, k.Select, k.Quit, k.Help}
+	return []key.Binding{k.Refresh, k.Sort, k.Portfolio, k.Dashboard, k.Alerts, k.Privacy, k.Select, k.Acknowledge, k.Snooze, k.Disable, k.Resume, k.Quit, k.Help}
 }
 
 func (k keyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{{k.Refresh, k.Sort, k.Portfolio, k.Dashboard, k.Privacy, k.Select, k.Back, k.Quit, k.Help}}
+	return [][]key.Binding{
+		{k.Refresh, k.Sort, k.Portfolio, k.Dashboard, k.Alerts, k.Privacy, k.Select, k.Back, k.Quit, k.Help},
+		{k.Acknowledge, k.Snooze, k.SnoozeSession, k.Disable, k.Resume},
+	}
 }
 
-var defaultKeyMap = keyMap{
-	Refresh: key.NewBinding(
-		key.WithKeys("r"),
-		key.WithHelp("r", "refresh"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q", "quit"),
-	),
-	Help: key.NewBinding(
-		key.WithKeys("h", "help"),
-		key.WithHelp("h", "help"),
-	),
-	Sort: key.NewBinding(
-		key.WithKeys("s"),
-		key.WithHelp("s", "sort order"),
-	),
-	Select: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "details"),
-	),
-	Back: key.NewBinding(
-		key.WithKeys("esc", "backspace"),
-		key.WithHelp("esc", "back"),
-	),
-	Portfolio: key.NewBinding(
-		key.WithKeys("p"),
-		key.WithHelp("p", "portfolio"),
-	),
-	Dashboard: key.NewBinding(
-		key.WithKeys("d"),
-		key.WithHelp("d", "dashboard"),
-	),
-	Privacy: key.NewBinding(
-		key.WithKeys("P"),
-		key.WithHelp("P", "privacy mode"),
-	),
+// newKeyMap returns the default keybindings, with help in the current language
+func newKeyMap() keyMap {
+	return keyMap{
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", i18n.T("refresh")),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", i18n.T("quit")),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("h", "help"),
+			key.WithHelp("h", i18n.T("help")),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", i18n.T("sort order")),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", i18n.T("details")),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "backspace"),
+			key.WithHelp("esc", i18n.T("back")),
+		),
+		Portfolio: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", i18n.T("portfolio")),
+		),
+		Dashboard: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", i18n.T("dashboard")),
+		),
+		Alerts: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", i18n.T("alerts")),
+		),
+		Privacy: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", i18n.T("privacy mode")),
+		),
+		Acknowledge: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", i18n.T("acknowledge")),
+		),
+		Snooze: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", i18n.T("snooze")),
+		),
+		SnoozeSession: key.NewBinding(
+			key.WithKeys("Z"),
+			key.WithHelp("Z", i18n.T("snooze until next session")),
+		),
+		Disable: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", i18n.T("disable")),
+		),
+		Resume: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", i18n.T("resume")),
+		),
+	}
 }
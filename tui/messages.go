@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/rule"
 	"github.com/congregalis/stock-ping/stock"
 )
 
@@ -11,11 +12,20 @@ import (
 type tickMsg time.Time
 type splashTimeoutMsg struct{}
 type refreshMsg struct{}
+type refreshDoneMsg struct{}
 type configReloadMsg struct{ cfg *config.Config }
 type stockUpdateMsg struct {
-	symbol string
-	quote  *stock.Quote
-	err    error
+	symbol   string
+	quote    *stock.Quote
+	candles  *stock.Candle
+	local    *stock.Candle // Daily bars from recorded quotes, for changes
+	intraday map[string]*stock.Candle
+	legs     map[string]*rule.Leg
+	err      error
+	warning  string // Shown in the status line, e.g. a failure to record the tick
+}
+type holdingQuotesMsg struct {
+	quotes map[string]*stock.Quote // Quotes of holdings without a rule
 }
 type marketStatusMsg struct {
 	open     bool
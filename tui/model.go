@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"maps"
 	"sort"
 	"strings"
 	"time"
@@ -11,9 +12,11 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/congregalis/stock-ping/config"
+	"github.com/congregalis/stock-ping/i18n"
 	"github.com/congregalis/stock-ping/notify"
 	"github.com/congregalis/stock-ping/rule"
 	"github.com/congregalis/stock-ping/stock"
+	"github.com/congregalis/stock-ping/store"
 	table "github.com/evertras/bubble-table/table"
 )
 
@@ -22,8 +25,18 @@ const (
 	ViewPortfolio = iota
 	ViewDashboard
 	ViewTrend
+	ViewAlerts
 )
 
+// Trend view plots trendDays bars but fetches trendLookbackDays so indicators are warmed up
+const (
+	trendDays         = 30
+	trendLookbackDays = 120
+)
+
+// Alerts view lists the alerts of the last alertHistoryDays, newest first
+const alertHistoryDays = 30
+
 // StockData holds the current state of a stock
 type StockData struct {
 	Symbol        string
@@ -37,8 +50,15 @@ type StockData struct {
 	LastUpdate    time.Time
 	Triggered     bool
 	TriggerReason string
+	Severity      config.Severity // Highest severity of the conditions currently met
+	Acked         bool            // Every condition met has been acknowledged
+	AlertingRules []string        // IDs of the rules with conditions met
+	Silence       *rule.Silence   // Set while rules of the stock are snoozed or disabled
 	Error         string
 	Market        string
+	// Trailing stop level and the peak it trails, 0 if none
+	TrailingStop float64
+	TrailingPeak float64
 	// Portfolio fields
 	Quantity  float64
 	CostPrice float64
@@ -59,43 +79,56 @@ type Model struct {
 	trendLoading bool
 	trendError   error
 
+	portfolioAlerts []string                // Conditions of portfolio rules currently met
+	holdingQuotes   map[string]*stock.Quote // Latest quotes of holdings without a rule, for portfolio rules
+
+	// Alerts View State
+	alertRecords []store.AlertRecord
+	alertsError  error
+
 	// Services
 	cfg         *config.Config
 	stockClient *stock.Client
 	notifier    *notify.Notifier
 	evaluator   *rule.Evaluator
+	ticks       *store.TickStore
+	alertLog    *store.AlertLog
+	candles     *stock.CandleCache
 
 	// Components
 	table          table.Model
 	portfolioTable table.Model
+	alertsTable    table.Model
 	help           help.Model
 	keys           keyMap
 
 	// Internal State
-	triggeredState map[string]map[string]bool
-	lastRefresh    time.Time
-	configPath     string
-	statusMessage  string
-	width          int
-	height         int
-	quitting       bool
-	sortAscending  bool
-	showSplash     bool
-	holdingsCount  int
-	privacyMode    bool
+	tracker       *rule.Tracker
+	alerts        *notify.Alerter // Sends and records alerts, collecting them into the digest
+	lastRefresh   time.Time
+	configPath    string
+	statusMessage string
+	width         int
+	height        int
+	quitting      bool
+	sortAscending bool
+	showSplash    bool
+	holdingsCount int
+	privacyMode   bool
 }
 
 // NewModel creates a new TUI model
-func NewModel(cfg *config.Config, stockClient *stock.Client, notifier *notify.Notifier, configPath string) Model {
+func NewModel(cfg *config.Config, stockClient *stock.Client, notifier *notify.Notifier, ticks *store.TickStore, alertLog *store.AlertLog, state rule.StateStore, configPath string) Model {
 	// Dashboard table columns
 	columns := []table.Column{
-		table.NewFlexColumn("symbol", "Symbol", 2),
-		table.NewColumn("price", "Price", 12),
-		table.NewColumn("change", "Change", 25),
-		table.NewColumn("open", "Open", 12),
-		table.NewColumn("day_range", "Day Range", 25),
-		table.NewColumn("prev_close", "Prev Close", 12),
-		table.NewColumn("updated", "Updated", 10),
+		table.NewFlexColumn("symbol", i18n.T("Symbol"), 2),
+		table.NewColumn("price", i18n.T("Price"), 12),
+		table.NewColumn("change", i18n.T("Change"), 25),
+		table.NewColumn("open", i18n.T("Open"), 12),
+		table.NewColumn("day_range", i18n.T("Day Range"), 25),
+		table.NewColumn("prev_close", i18n.T("Prev Close"), 12),
+		table.NewColumn("trailing_stop", i18n.T("Trailing Stop"), 24),
+		table.NewColumn("updated", i18n.T("Updated"), 10),
 	}
 
 	t := table.New(columns).
@@ -107,12 +140,12 @@ func NewModel(cfg *config.Config, stockClient *stock.Client, notifier *notify.No
 
 	// Portfolio table columns (Optimized for space)
 	portfolioColumns := []table.Column{
-		table.NewFlexColumn("symbol", "Symbol", 2),
-		table.NewColumn("price", "Price", 12),
-		table.NewColumn("change", "Change", 25),
-		table.NewColumn("quantity", "Quantity", 10),
-		table.NewColumn("cost", "Cost", 12),
-		table.NewColumn("pl", "P/L", 25),
+		table.NewFlexColumn("symbol", i18n.T("Symbol"), 2),
+		table.NewColumn("price", i18n.T("Price"), 12),
+		table.NewColumn("change", i18n.T("Change"), 25),
+		table.NewColumn("quantity", i18n.T("Quantity"), 10),
+		table.NewColumn("cost", i18n.T("Cost"), 12),
+		table.NewColumn("pl", i18n.T("P/L"), 25),
 	}
 
 	pt := table.New(portfolioColumns).
@@ -122,15 +155,32 @@ func NewModel(cfg *config.Config, stockClient *stock.Client, notifier *notify.No
 		Focused(true).
 		WithPageSize(20)
 
+	// Alert history columns
+	alertColumns := []table.Column{
+		table.NewColumn("time", i18n.T("Time"), 18),
+		table.NewColumn("subject", i18n.T("Symbol"), 16),
+		table.NewColumn("severity", i18n.T("Severity"), 12),
+		table.NewFlexColumn("alert", i18n.T("Alert"), 3),
+		table.NewColumn("delivery", i18n.T("Delivery"), 24),
+	}
+
+	at := table.New(alertColumns).
+		HeaderStyle(tableHeaderStyle).
+		HighlightStyle(tableSelectedStyle).
+		BorderRounded().
+		Focused(true).
+		WithPageSize(20)
+
 	stocks := make(map[string]*StockData)
 	var stockOrder []string
-	for _, r := range cfg.Rules {
-		stocks[r.Symbol] = &StockData{
-			Symbol: r.Symbol,
-			Name:   r.Name,
-			Market: r.Market,
+	for _, symbol := range cfg.Symbols() {
+		name, market := cfg.SymbolInfo(symbol)
+		stocks[symbol] = &StockData{
+			Symbol: symbol,
+			Name:   name,
+			Market: market,
 		}
-		stockOrder = append(stockOrder, r.Symbol)
+		stockOrder = append(stockOrder, symbol)
 	}
 
 	// Load holdings into stocks
@@ -147,20 +197,27 @@ func NewModel(cfg *config.Config, stockClient *stock.Client, notifier *notify.No
 		viewMode:       ViewPortfolio, // Default to portfolio view
 		table:          t,
 		portfolioTable: pt,
+		alertsTable:    at,
 		help:           help.New(),
-		keys:           defaultKeyMap,
+		keys:           newKeyMap(),
 		stocks:         stocks,
 		stockOrder:     stockOrder,
 		cfg:            cfg,
 		stockClient:    stockClient,
 		notifier:       notifier,
 		evaluator:      rule.NewEvaluator(),
-		triggeredState: make(map[string]map[string]bool),
+		ticks:          ticks,
+		alertLog:       alertLog,
+		candles:        stock.NewCandleCache(stockClient, 15*time.Minute),
+		tracker:        rule.NewTracker(state),
+		alerts:         notify.NewAlerter(notifier, alertLog),
 		configPath:     configPath,
 		sortAscending:  false, // Default to Descending
 		showSplash:     true,
 		holdingsCount:  holdingsCount,
+		holdingQuotes:  make(map[string]*stock.Quote),
 	}
+	m.tracker.SetQuietHours(cfg.QuietHours)
 
 	// Apply initial sort (Change Descending)
 	m.SortByChange()
@@ -190,31 +247,137 @@ func (m Model) refreshAllStocks(force bool) tea.Cmd {
 		s := symbol
 
 		// Check if market is open for this stock
-		// If data exists, check its market. If not, look up rule.
+		// If data exists, check its market. If not, look up its rules.
 		market := stock.MarketUS
 		if data, ok := m.stocks[s]; ok && data.Market != "" {
 			market = data.Market
-		} else if rule := m.cfg.GetRule(s); rule != nil && rule.Market != "" {
-			market = rule.Market
+		} else if _, ruleMarket := m.cfg.SymbolInfo(s); ruleMarket != "" {
+			market = ruleMarket
 		}
 
+		// Daily and intraday candles, enough for every rule on the symbol
+		rules := m.cfg.RulesForSymbol(s)
+		needs := rule.CandleNeeds(rules, m.cfg.GetHolding(s))
+
 		if force || stock.IsMarketOpen(market) {
 			cmds = append(cmds, func() tea.Msg {
 				quote, err := m.stockClient.GetQuote(s, market)
-				return stockUpdateMsg{symbol: s, quote: quote, err: err}
+				if err != nil {
+					return stockUpdateMsg{symbol: s, err: err}
+				}
+				msg := stockUpdateMsg{symbol: s, quote: quote}
+				if m.ticks != nil {
+					if err := m.ticks.Append(quote); err != nil {
+						msg.warning = i18n.T("⚠️  Failed to record tick: %v", err)
+					} else if err := m.ticks.MaintainErr(); err != nil {
+						msg.warning = "⚠️  " + err.Error()
+					}
+				}
+				for res, bars := range needs {
+					candles, err := m.candles.Get(s, market, res, bars)
+					if err != nil {
+						if lookback := rule.ChangeLookback(rules); res == "D" && lookback > 0 && m.ticks != nil {
+							// Changes can fall back to daily bars built from the recorded quotes
+							if msg.local, err = m.ticks.Daily(s, market, lookback); err == nil && msg.warning == "" {
+								msg.warning = i18n.T("📼 %s: using daily bars from recorded quotes for changes", s)
+							}
+						}
+						continue
+					}
+					if res == "D" {
+						msg.candles = candles
+					} else {
+						if msg.intraday == nil {
+							msg.intraday = make(map[string]*stock.Candle)
+						}
+						msg.intraday[res] = candles
+					}
+				}
+				msg.legs = m.fetchPairLegs(rules)
+				return msg
 			})
 		}
 	}
-	return tea.Batch(cmds...)
+	if cmd := m.fetchHoldingQuotes(force); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	// Portfolio rules run once every quote of the cycle has been applied
+	return tea.Sequence(tea.Batch(cmds...), func() tea.Msg { return refreshDoneMsg{} })
+}
+
+// fetchHoldingQuotes fetches the quotes of holdings that have no rule, and so
+// are not refreshed with the watched stocks, when portfolio rules need them
+func (m Model) fetchHoldingQuotes(force bool) tea.Cmd {
+	if len(m.cfg.PortfolioRules) == 0 {
+		return nil
+	}
+	type holding struct{ symbol, market string }
+	var holdings []holding
+	for _, h := range m.cfg.Holdings {
+		if _, ok := m.stocks[h.Symbol]; ok {
+			continue
+		}
+		_, market := m.cfg.SymbolInfo(h.Symbol)
+		if force || stock.IsMarketOpen(market) {
+			holdings = append(holdings, holding{h.Symbol, market})
+		}
+	}
+	if len(holdings) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		quotes := make(map[string]*stock.Quote)
+		for _, h := range holdings {
+			if quote, err := m.stockClient.GetQuote(h.symbol, h.market); err == nil {
+				quotes[h.symbol] = quote
+			}
+		}
+		return holdingQuotesMsg{quotes: quotes}
+	}
+}
+
+// fetchPairLegs fetches the other legs of the enabled pair rules
+func (m Model) fetchPairLegs(rules []*config.Rule) map[string]*rule.Leg {
+	var legs map[string]*rule.Leg
+	for _, r := range rules {
+		if !r.IsEnabled() || r.Pair == nil {
+			continue
+		}
+		for _, l := range r.Pair.Legs() {
+			if _, ok := legs[l.Symbol]; ok {
+				continue
+			}
+			quote, err := m.stockClient.GetQuote(l.Symbol, l.Market)
+			if err != nil {
+				continue
+			}
+			leg := &rule.Leg{Quote: quote}
+			if lookback := rule.PairLookback(r); lookback > 0 {
+				leg.Candles, _ = m.candles.Daily(l.Symbol, l.Market, lookback)
+			}
+			if legs == nil {
+				legs = make(map[string]*rule.Leg)
+			}
+			legs[l.Symbol] = leg
+		}
+	}
+	return legs
 }
 
 func (m Model) fetchTrendData(symbol string) tea.Cmd {
+	market := ""
+	if data, ok := m.stocks[symbol]; ok {
+		market = data.Market
+	}
 	return func() tea.Msg {
-		// Fetch Daily resolution for last 30 days
+		// Fetch Daily resolution with enough history to warm up indicators
 		to := time.Now().Unix()
-		from := time.Now().AddDate(0, 0, -30).Unix()
+		from := time.Now().AddDate(0, 0, -trendLookbackDays).Unix()
 
-		candles, err := m.stockClient.GetCandles(symbol, "D", from, to)
+		candles, err := m.stockClient.GetCandles(symbol, market, "D", from, to)
 		return candleUpdateMsg{symbol: symbol, candles: candles, err: err}
 	}
 }
@@ -238,6 +401,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.table = m.table.WithTargetWidth(msg.Width - 4).WithPageSize(pageSize)
 		m.portfolioTable = m.portfolioTable.WithTargetWidth(msg.Width - 4).WithPageSize(pageSize)
+		m.alertsTable = m.alertsTable.WithTargetWidth(msg.Width - 4).WithPageSize(pageSize)
 
 	case tea.KeyMsg:
 		if m.showSplash {
@@ -248,6 +412,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Common keys
 		if key.Matches(msg, m.keys.Quit) {
 			m.quitting = true
+			m.flushBatch(true)
 			return m, tea.Quit
 		}
 
@@ -255,41 +420,49 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.privacyMode = !m.privacyMode
 			m.updatePortfolioTableRows()
 			if m.privacyMode {
-				m.statusMessage = "🙈 Privacy Mode: ON"
+				m.statusMessage = i18n.T("🙈 Privacy Mode: ON")
 			} else {
-				m.statusMessage = "🐵 Privacy Mode: OFF"
+				m.statusMessage = i18n.T("🐵 Privacy Mode: OFF")
 			}
 			return m, nil
 		}
 
+		// Alert actions on the selected stock
+		if m.viewMode == ViewPortfolio || m.viewMode == ViewDashboard {
+			row := m.table.HighlightedRow()
+			if m.viewMode == ViewPortfolio {
+				row = m.portfolioTable.HighlightedRow()
+			}
+			if m.alertAction(msg, rowSymbol(row)) {
+				return m, nil
+			}
+		}
+
 		// View specific keys
 		if m.viewMode == ViewPortfolio {
 			switch {
 			case key.Matches(msg, m.keys.Refresh):
-				m.statusMessage = "Refreshing..."
+				m.statusMessage = i18n.T("Refreshing...")
 				return m, m.refreshAllStocks(true)
 			case key.Matches(msg, m.keys.Sort):
 				m.sortAscending = !m.sortAscending
 				m.SortByChange()
-				orderStr := "Descending"
+				orderStr := i18n.T("Descending")
 				if m.sortAscending {
-					orderStr = "Ascending"
+					orderStr = i18n.T("Ascending")
 				}
-				m.statusMessage = "Sorted: " + orderStr
+				m.statusMessage = i18n.T("Sorted: %s", orderStr)
 				return m, nil
 			case key.Matches(msg, m.keys.Dashboard):
 				m.viewMode = ViewDashboard
 				return m, nil
+			case key.Matches(msg, m.keys.Alerts):
+				m.viewMode = ViewAlerts
+				m.loadAlerts()
+				return m, nil
 			case key.Matches(msg, m.keys.Select):
 				// Switch to Trend View from portfolio
-				selectedRow := m.portfolioTable.HighlightedRow()
-				if selectedRow.Data != nil {
-					raw := selectedRow.Data["symbol"].(string)
-					parts := strings.Split(raw, "(")
-					symbol := strings.TrimSpace(parts[0])
-					if idx := strings.LastIndex(symbol, " "); idx != -1 {
-						symbol = symbol[idx+1:]
-					}
+				if symbol := rowSymbol(m.portfolioTable.HighlightedRow()); symbol != "" {
 					m.selectedSymbol = symbol
 					m.viewMode = ViewTrend
 					m.trendLoading = true
@@ -301,35 +474,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else if m.viewMode == ViewDashboard {
 			switch {
 			case key.Matches(msg, m.keys.Refresh):
-				m.statusMessage = "Refreshing..."
+				m.statusMessage = i18n.T("Refreshing...")
 				return m, m.refreshAllStocks(true)
 			case key.Matches(msg, m.keys.Sort):
 				m.sortAscending = !m.sortAscending
 				m.SortByChange()
-				orderStr := "Descending"
+				orderStr := i18n.T("Descending")
 				if m.sortAscending {
-					orderStr = "Ascending"
+					orderStr = i18n.T("Ascending")
 				}
-				m.statusMessage = "Sorted: " + orderStr
+				m.statusMessage = i18n.T("Sorted: %s", orderStr)
 				return m, nil
 			case key.Matches(msg, m.keys.Portfolio):
 				m.viewMode = ViewPortfolio
 				return m, nil
+			case key.Matches(msg, m.keys.Alerts):
+				m.viewMode = ViewAlerts
+				m.loadAlerts()
+				return m, nil
 			case key.Matches(msg, m.keys.Select):
 				// Switch to Trend View
-				selectedRow := m.table.HighlightedRow()
-				if selectedRow.Data != nil {
-					// Parse symbol from "Symbol" or "Symbol(Name)"
-					raw := selectedRow.Data["symbol"].(string)
-					// Remove prefixes if any (like icons)
-					// Remove suffixes
-					parts := strings.Split(raw, "(")
-					symbol := strings.TrimSpace(parts[0])
-					// Handle cases where symbol might be "⚠️ SYMBOL"
-					if idx := strings.LastIndex(symbol, " "); idx != -1 {
-						symbol = symbol[idx+1:]
-					}
-
+				if symbol := rowSymbol(m.table.HighlightedRow()); symbol != "" {
 					m.selectedSymbol = symbol
 					m.viewMode = ViewTrend
 					m.trendLoading = true
@@ -353,6 +518,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selectedSymbol = ""
 				return m, nil
 			}
+		} else if m.viewMode == ViewAlerts {
+			switch {
+			case key.Matches(msg, m.keys.Refresh):
+				m.loadAlerts()
+				return m, nil
+			case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Portfolio):
+				m.viewMode = ViewPortfolio
+				return m, nil
+			case key.Matches(msg, m.keys.Dashboard):
+				m.viewMode = ViewDashboard
+				return m, nil
+			}
 		}
 
 	case tickMsg:
@@ -360,10 +537,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.tickCmd())
 
 	case stockUpdateMsg:
+		m.statusMessage = msg.warning
 		m.updateStock(msg)
 		m.SortByChange()
 		m.lastRefresh = time.Now()
-		m.statusMessage = ""
+
+	case holdingQuotesMsg:
+		maps.Copy(m.holdingQuotes, msg.quotes)
+
+	case refreshDoneMsg:
+		m.checkPortfolioRules()
+		m.sendDigest()
+		m.flushBatch(false)
+		if m.viewMode == ViewAlerts {
+			m.loadAlerts()
+		}
 
 	case candleUpdateMsg:
 		if msg.symbol == m.selectedSymbol {
@@ -377,8 +565,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case configReloadMsg:
 		m.cfg = msg.cfg
+		m.tracker.SetQuietHours(m.cfg.QuietHours)
 		m.reloadRules()
-		m.statusMessage = "🔄 Config reloaded"
+		m.statusMessage = i18n.T("🔄 Config reloaded")
 
 	case splashTimeoutMsg:
 		m.showSplash = false
@@ -391,6 +580,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	} else if m.viewMode == ViewDashboard {
 		m.table, cmd = m.table.Update(msg)
 		cmds = append(cmds, cmd)
+	} else if m.viewMode == ViewAlerts {
+		m.alertsTable, cmd = m.alertsTable.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -416,48 +608,254 @@ func (m *Model) updateStock(msg stockUpdateMsg) {
 	data.LastUpdate = time.Now()
 	data.Error = ""
 
-	// Evaluate rules
-	r := m.cfg.GetRule(msg.symbol)
-	if r == nil {
-		return
+	// Evaluate every enabled rule against the same quote
+	in := rule.Input{
+		Quote:        msg.quote,
+		Candles:      msg.candles,
+		LocalCandles: msg.local,
+		Holding:      m.cfg.GetHolding(msg.symbol),
+		Market:       data.Market,
+		Intraday:     msg.intraday,
+		Legs:         msg.legs,
 	}
 
-	result := m.evaluator.Evaluate(r, msg.quote)
+	data.Triggered = false
+	data.TriggerReason = ""
+	data.Severity = ""
+	data.Acked = false
+	data.AlertingRules = nil
+	data.Silence = nil
+	data.TrailingStop = 0
+	data.TrailingPeak = 0
+	outcomes, err := m.tracker.EvaluateAll(m.evaluator, m.cfg.RulesForSymbol(msg.symbol), in, time.Now())
+	if err != nil {
+		m.statusMessage = i18n.T("⚠️ Failed to save alert state: %v", err)
+	}
+	allAcked := true
+	for _, o := range outcomes {
+		// Show the tightest trailing stop across the symbol's rules
+		if o.TrailingStop > data.TrailingStop {
+			data.TrailingStop = o.TrailingStop
+			data.TrailingPeak = o.TrailingPeak
+		}
+
+		// Conditions of snoozed and disabled rules are not shown
+		if o.Silence != nil {
+			if data.Silence == nil || o.Silence.Disabled() {
+				data.Silence = o.Silence
+			}
+			continue
+		}
+
+		// Show the most severe condition met across the symbol's rules
+		if len(o.Conditions) > 0 {
+			data.AlertingRules = append(data.AlertingRules, o.Rule.ID)
+		}
+		for _, c := range o.Conditions {
+			if !data.Triggered || c.Severity.Rank() > data.Severity.Rank() {
+				data.Triggered = true
+				data.TriggerReason = c.Text
+				data.Severity = c.Severity
+			}
+			allAcked = allAcked && o.IsAcked(c)
+		}
 
-	// Build current conditions map
-	currentConditions := make(map[string]bool)
-	for _, reason := range result.Reasons {
-		currentConditions[reason] = true
+		// Send notification only for new triggers
+		m.sendAlert(msg.symbol, msg.quote, o.New, o.FormatNotification)
 	}
+	data.Acked = data.Triggered && allAcked
+}
 
-	// Initialize state if needed
-	if m.triggeredState[msg.symbol] == nil {
-		m.triggeredState[msg.symbol] = make(map[string]bool)
+// alertAction acknowledges, snoozes, disables or resumes the alerting rules
+// of symbol if msg is one of those keys. The change is saved to the shared
+// alert state, so watch respects it too.
+func (m *Model) alertAction(msg tea.KeyMsg, symbol string) bool {
+	if !key.Matches(msg, m.keys.Acknowledge, m.keys.Snooze, m.keys.SnoozeSession, m.keys.Disable, m.keys.Resume) {
+		return false
+	}
+	data, ok := m.stocks[symbol]
+	if !ok {
+		return true
 	}
 
-	// Find newly triggered conditions
-	var newlyTriggered []string
-	for reason := range currentConditions {
-		if !m.triggeredState[msg.symbol][reason] {
-			newlyTriggered = append(newlyTriggered, reason)
+	now := time.Now()
+	var err error
+	if key.Matches(msg, m.keys.Resume) {
+		var ids []string
+		for _, r := range m.cfg.RulesForSymbol(symbol) {
+			ids = append(ids, r.ID)
+		}
+		var n int
+		if n, err = m.tracker.Resume(ids); n > 0 {
+			data.Silence = nil
+			data.Acked = false
+			m.statusMessage = i18n.T("🔔 Alerts of %s resumed", symbol)
+		} else {
+			m.statusMessage = i18n.T("Nothing to resume for %s", symbol)
+		}
+	} else if len(data.AlertingRules) == 0 {
+		m.statusMessage = i18n.T("No alerts met for %s", symbol)
+		return true
+	} else {
+		ids := data.AlertingRules
+		switch {
+		case key.Matches(msg, m.keys.Acknowledge):
+			_, err = m.tracker.Acknowledge(ids)
+			data.Acked = true
+			m.statusMessage = i18n.T("✅ Alerts of %s acknowledged", symbol)
+		case key.Matches(msg, m.keys.Disable):
+			err = m.tracker.Disable(ids, now)
+			data.Silence = &rule.Silence{At: now}
+			m.statusMessage = i18n.T("🔕 Alerts of %s disabled until resumed", symbol)
+		default:
+			until := now.Add(m.cfg.SnoozeDuration())
+			if key.Matches(msg, m.keys.SnoozeSession) {
+				until = stock.NextSessionOpen(data.Market, now)
+			}
+			err = m.tracker.Snooze(ids, until, now)
+			data.Silence = &rule.Silence{Until: until, At: now}
+			m.statusMessage = i18n.T("💤 Alerts of %s snoozed until %s", symbol, until.Local().Format("01-02 15:04"))
+		}
+		if data.Silence != nil {
+			data.Triggered = false
+			data.AlertingRules = nil
 		}
 	}
+	if err != nil {
+		m.statusMessage = i18n.T("⚠️ Failed to save alert state: %v", err)
+	}
+	m.SortByChange()
+	return true
+}
 
-	// Update state
-	m.triggeredState[msg.symbol] = currentConditions
+// rowSymbol returns the symbol of a table row, e.g. "AAPL" from "🔴 AAPL(Apple)"
+func rowSymbol(row table.Row) string {
+	if row.Data == nil {
+		return ""
+	}
+	raw, _ := row.Data["symbol"].(string)
+	symbol := strings.TrimSpace(strings.Split(raw, "(")[0])
+	if idx := strings.LastIndex(symbol, " "); idx != -1 {
+		symbol = symbol[idx+1:]
+	}
+	return symbol
+}
 
-	data.Triggered = result.Triggered()
-	if len(result.Reasons) > 0 {
-		data.TriggerReason = result.Reasons[0]
-	} else {
-		data.TriggerReason = ""
+// alertBadge returns the icon shown before a stock: the severity of its alerts,
+// 💤 or 🔕 while its rules are snoozed or disabled, ✅ once acknowledged
+func alertBadge(data *StockData) string {
+	switch {
+	case data.Triggered && !data.Acked:
+		return rule.SeverityIcon(data.Severity)
+	case data.Silence != nil && data.Silence.Disabled():
+		return "🔕"
+	case data.Silence != nil:
+		return "💤"
+	case data.Triggered:
+		return "✅"
+	}
+	return ""
+}
+
+// checkPortfolioRules evaluates the portfolio rules against the latest prices
+func (m *Model) checkPortfolioRules() {
+	if len(m.cfg.PortfolioRules) == 0 {
+		m.portfolioAlerts = nil
+		return
 	}
 
-	// Send notification only for new triggers
-	if len(newlyTriggered) > 0 && m.notifier.IsConfigured() {
-		title, body := result.FormatNotification()
-		m.notifier.SendWithGroup(title, body, "stock-ping")
+	quotes := make(map[string]*stock.Quote)
+	for symbol, data := range m.stocks {
+		if data.Error == "" && data.Price > 0 {
+			quotes[symbol] = &stock.Quote{Symbol: symbol, CurrentPrice: data.Price, PrevClose: data.PrevClose}
+		}
+	}
+	for symbol, quote := range m.holdingQuotes {
+		if _, ok := m.stocks[symbol]; !ok {
+			quotes[symbol] = quote
+		}
 	}
+	portfolio := rule.NewPortfolio(m.cfg.Holdings, quotes)
+	outcomes, err := m.tracker.EvaluatePortfolio(m.evaluator, m.cfg.PortfolioRules, portfolio, time.Now())
+	if err != nil {
+		m.statusMessage = i18n.T("⚠️ Failed to save alert state: %v", err)
+	}
+	if len(portfolio.Missing) > 0 {
+		m.statusMessage = i18n.T("⚠️ Portfolio rules skipped, no quote for %s", strings.Join(portfolio.Missing, ", "))
+		return
+	}
+	if len(portfolio.Positions) == 0 {
+		return
+	}
+
+	m.portfolioAlerts = nil
+	for _, o := range outcomes {
+		for _, c := range o.Conditions {
+			m.portfolioAlerts = append(m.portfolioAlerts, fmt.Sprintf("[%s] %s", c.RuleID, c.Text))
+		}
+		m.sendAlert(o.Subject(), nil, o.New, o.FormatNotification)
+	}
+}
+
+// sendAlert notifies about the new conditions of subject raised on quote, or
+// adds them to the digest unless they are severe enough to go out right away
+func (m *Model) sendAlert(subject string, quote *stock.Quote, conditions []rule.Condition, format func() (string, string)) {
+	m.reportDelivery(m.alerts.Send(m.cfg, subject, quote, conditions, format))
+}
+
+// flushBatch sends the digest of collected alerts once its window has passed,
+// or right away if force is set
+func (m *Model) flushBatch(force bool) {
+	m.reportDelivery(m.alerts.Flush(m.cfg, force))
+}
+
+// sendDigest sends the alerts queued during quiet hours once they are over
+func (m *Model) sendDigest() {
+	m.reportDelivery(m.alerts.FlushQuietHours(m.cfg, m.tracker))
+}
+
+// reportDelivery shows in the status bar why alerts could not be delivered
+func (m *Model) reportDelivery(d notify.Delivery) {
+	if d.SendErr != nil {
+		m.statusMessage = i18n.T("⚠️ Failed to send notification: %v", d.SendErr)
+	}
+	if d.RecordErr != nil {
+		m.statusMessage = i18n.T("⚠️ Failed to record alerts: %v", d.RecordErr)
+	}
+	if d.StateErr != nil {
+		m.statusMessage = i18n.T("⚠️ Failed to save alert state: %v", d.StateErr)
+	}
+}
+
+// loadAlerts reloads the alert history shown in the alerts view
+func (m *Model) loadAlerts() {
+	m.alertRecords, m.alertsError = nil, nil
+	if m.alertLog != nil {
+		from := time.Now().AddDate(0, 0, -alertHistoryDays)
+		m.alertRecords, m.alertsError = m.alertLog.Query(store.AlertFilter{From: from})
+	}
+	m.updateAlertsTableRows()
+}
+
+func (m *Model) updateAlertsTableRows() {
+	var rows []table.Row
+	for i := len(m.alertRecords) - 1; i >= 0; i-- {
+		r := m.alertRecords[i]
+		severity := string(r.Severity)
+		if icon := rule.SeverityIcon(r.Severity); icon != "" {
+			severity = icon + " " + severity
+		}
+
+		row := table.NewRow(table.RowData{
+			"time":     r.Time.Local().Format("01-02 15:04:05"),
+			"subject":  r.Subject,
+			"severity": severity,
+			"alert":    fmt.Sprintf("[%s] %s", r.RuleID, r.Text),
+			"delivery": r.DeliveryText(),
+		})
+		rows = append(rows, row.WithStyle(severityStyle(r.Severity)))
+	}
+	m.alertsTable = m.alertsTable.WithRows(rows)
 }
 
 func (m *Model) updateTableRows() {
@@ -478,12 +876,16 @@ func (m *Model) updateTableRows() {
 		openStr := "--"
 		dayRangeStr := "--"
 		prevCloseStr := "--"
+		trailingStr := "--"
 		updatedStr := "--"
 
 		if data.Error != "" {
 			displayName = "❌ " + displayName
 			updatedStr = "ERROR"
 		} else if data.Price > 0 {
+			if badge := alertBadge(data); badge != "" {
+				displayName = badge + " " + displayName
+			}
 			priceStr = fmt.Sprintf("$%.2f", data.Price)
 			openStr = fmt.Sprintf("$%.2f", data.Open)
 			prevCloseStr = fmt.Sprintf("$%.2f", data.PrevClose)
@@ -503,20 +905,34 @@ func (m *Model) updateTableRows() {
 				changeStr = redStyle.Render(fmt.Sprintf("-$%.2f (%.2f%%)", -priceChange, data.Change))
 			}
 
+			if data.TrailingStop > 0 {
+				trailingStr = i18n.T("$%.2f (peak $%.2f)", data.TrailingStop, data.TrailingPeak)
+				if data.Price <= data.TrailingStop {
+					trailingStr = redStyle.Render(trailingStr)
+				}
+			}
+
 			if !data.LastUpdate.IsZero() {
 				updatedStr = data.LastUpdate.Format("15:04:05")
 			}
 		}
 
-		rows = append(rows, table.NewRow(table.RowData{
-			"symbol":     displayName,
-			"price":      priceStr,
-			"change":     changeStr,
-			"open":       openStr,
-			"day_range":  dayRangeStr,
-			"prev_close": prevCloseStr,
-			"updated":    updatedStr,
-		}))
+		row := table.NewRow(table.RowData{
+			"symbol":        displayName,
+			"price":         priceStr,
+			"change":        changeStr,
+			"open":          openStr,
+			"day_range":     dayRangeStr,
+			"prev_close":    prevCloseStr,
+			"trailing_stop": trailingStr,
+			"updated":       updatedStr,
+		})
+		if data.Triggered && data.Acked {
+			row = row.WithStyle(dimStyle)
+		} else if data.Triggered {
+			row = row.WithStyle(severityStyle(data.Severity))
+		}
+		rows = append(rows, row)
 	}
 	m.table = m.table.WithRows(rows)
 }
@@ -525,18 +941,20 @@ func (m *Model) reloadRules() {
 	// Rebuild stocks map from new config
 	newStocks := make(map[string]*StockData)
 	var newOrder []string
-	for _, r := range m.cfg.Rules {
-		if existing, ok := m.stocks[r.Symbol]; ok {
-			existing.Name = r.Name
-			newStocks[r.Symbol] = existing
+	for _, symbol := range m.cfg.Symbols() {
+		name, market := m.cfg.SymbolInfo(symbol)
+		if existing, ok := m.stocks[symbol]; ok {
+			existing.Name = name
+			existing.Market = market
+			newStocks[symbol] = existing
 		} else {
-			newStocks[r.Symbol] = &StockData{
-				Symbol: r.Symbol,
-				Name:   r.Name,
-				Market: r.Market,
+			newStocks[symbol] = &StockData{
+				Symbol: symbol,
+				Name:   name,
+				Market: market,
 			}
 		}
-		newOrder = append(newOrder, r.Symbol)
+		newOrder = append(newOrder, symbol)
 	}
 	// Reload holdings
 	holdingsCount := 0
@@ -557,7 +975,7 @@ func (m *Model) reloadRules() {
 // View renders the UI
 func (m Model) View() string {
 	if m.quitting {
-		return "👋 Goodbye!\n"
+		return i18n.T("👋 Goodbye!\n")
 	}
 
 	if m.showSplash {
@@ -575,8 +993,10 @@ func (m Model) View() string {
 		return m.ViewDashboard()
 	case ViewTrend:
 		return m.ViewTrend()
+	case ViewAlerts:
+		return m.ViewAlerts()
 	default:
-		return "Unknown view"
+		return i18n.T("Unknown view")
 	}
 }
 
@@ -591,9 +1011,9 @@ func formatDuration(d time.Duration) string {
 	hours := int(d.Hours())
 	minutes := int(d.Minutes()) % 60
 	if hours > 0 {
-		return fmt.Sprintf("%dh%dm", hours, minutes)
+		return i18n.T("%dh%dm", hours, minutes)
 	}
-	return fmt.Sprintf("%dm", minutes)
+	return i18n.T("%dm", minutes)
 }
 
 // SortBySymbol sorts stocks alphabetically
@@ -604,9 +1024,14 @@ func (m *Model) SortBySymbol() {
 }
 
 // SortByChange sorts stocks by percent change
+// SortByChange sorts the stocks by percent change, keeping those with alerts
+// on top in order of severity
 func (m *Model) SortByChange() {
 	sort.Slice(m.stockOrder, func(i, j int) bool {
 		si, sj := m.stocks[m.stockOrder[i]], m.stocks[m.stockOrder[j]]
+		if ri, rj := alertRank(si), alertRank(sj); ri != rj {
+			return ri > rj
+		}
 		if m.sortAscending {
 			return si.Change < sj.Change
 		}
@@ -616,6 +1041,14 @@ func (m *Model) SortByChange() {
 	m.updatePortfolioTableRows()
 }
 
+// alertRank orders stocks by the severity of their alerts, -1 if none is met
+func alertRank(data *StockData) int {
+	if !data.Triggered {
+		return -1
+	}
+	return data.Severity.Rank()
+}
+
 func (m *Model) updatePortfolioTableRows() {
 	var rows []table.Row
 	for _, symbol := range m.stockOrder {
@@ -643,6 +1076,9 @@ func (m *Model) updatePortfolioTableRows() {
 		if data.Error != "" {
 			displayName = "❌ " + displayName
 		} else if data.Price > 0 {
+			if badge := alertBadge(data); badge != "" {
+				displayName = badge + " " + displayName
+			}
 			priceStr = fmt.Sprintf("$%.2f", data.Price)
 
 			// Calculate daily change display
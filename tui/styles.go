@@ -1,6 +1,9 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/congregalis/stock-ping/config"
+)
 
 // Color Palette (Catppuccin Mocha inspired)
 const (
@@ -57,6 +60,7 @@ var (
 	warnStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color(ColorYellow))
 	mutedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(ColorOverlay0))
 	dimStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color(ColorOverlay1))
+	infoStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color(ColorSky))
 
 	logoStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color(ColorBlue)).
@@ -93,3 +97,14 @@ const splashLogo = `
    ▀▀▀▄▄▄   ██  ██▀██ ██▀▀▀ ██▄█▀   ██▄▄█▀ ██ ███▄██ ██ ▄▄ 
    █████▀   ██  ▀███▀ ▀████ ██ ██   ██     ██ ██ ▀██ ▀███▀ 
 `
+
+// severityStyle returns the row style of a stock with an alert of the given severity
+func severityStyle(s config.Severity) lipgloss.Style {
+	switch s {
+	case config.SeverityCritical:
+		return redStyle.Bold(true)
+	case config.SeverityWarning:
+		return warnStyle
+	}
+	return infoStyle
+}
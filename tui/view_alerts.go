@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/congregalis/stock-ping/i18n"
+)
+
+// ViewAlerts renders the alert history view
+func (m Model) ViewAlerts() string {
+	var b strings.Builder
+
+	// Title
+	title := titleStyle.Render(i18n.T("🔔 Alert History"))
+	b.WriteString(title)
+	b.WriteString("\n\n")
+
+	// Table
+	switch {
+	case m.alertLog == nil:
+		b.WriteString(mutedStyle.Render(i18n.T("Alert history is not available.")))
+	case m.alertsError != nil:
+		b.WriteString(redStyle.Render(i18n.T("Error loading alerts: %v", m.alertsError)))
+	case len(m.alertRecords) == 0:
+		b.WriteString(mutedStyle.Render(i18n.T("No alerts in the last %d days.", alertHistoryDays)))
+	default:
+		b.WriteString(m.alertsTable.View())
+	}
+	b.WriteString("\n\n")
+
+	// Status bar
+	statusParts := []string{
+		i18n.T("Alerts: %d", len(m.alertRecords)),
+		i18n.T("Last %d days", alertHistoryDays),
+	}
+	if m.statusMessage != "" {
+		statusParts = append(statusParts, m.statusMessage)
+	}
+	b.WriteString(statusBarStyle.Render(strings.Join(statusParts, " • ")))
+	b.WriteString("\n\n")
+
+	// Help (Contextual)
+	b.WriteString(mutedStyle.Render(m.help.View(m.keys)))
+
+	return b.String()
+}
@@ -1,8 +1,9 @@
 package tui
 
 import (
-	"fmt"
 	"strings"
+
+	"github.com/congregalis/stock-ping/i18n"
 )
 
 // ViewDashboard renders the main dashboard view
@@ -23,11 +24,11 @@ func (m Model) ViewDashboard() string {
 
 	// Status bar
 	statusParts := []string{
-		fmt.Sprintf("Interval: %ds", m.cfg.Interval),
-		fmt.Sprintf("Stocks: %d", len(m.stocks)),
+		i18n.T("Interval: %ds", m.cfg.Interval),
+		i18n.T("Stocks: %d", len(m.stocks)),
 	}
 	if !m.lastRefresh.IsZero() {
-		statusParts = append(statusParts, fmt.Sprintf("Last: %s", m.lastRefresh.Format("15:04:05")))
+		statusParts = append(statusParts, i18n.T("Last: %s", m.lastRefresh.Format("15:04:05")))
 	}
 	if m.statusMessage != "" {
 		statusParts = append(statusParts, m.statusMessage)
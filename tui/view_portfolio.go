@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/congregalis/stock-ping/i18n"
 )
 
 // ViewPortfolio renders the portfolio holdings view
@@ -12,7 +14,7 @@ func (m Model) ViewPortfolio() string {
 	var b strings.Builder
 
 	// Title
-	title := titleStyle.Render("📈 Portfolio")
+	title := titleStyle.Render(i18n.T("📈 Portfolio"))
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
@@ -57,14 +59,14 @@ func (m Model) ViewPortfolio() string {
 		// Cards
 		valueCard := cardStyle.Render(
 			lipgloss.JoinVertical(lipgloss.Left,
-				summaryLabelStyle.Render("TOTAL VALUE"),
+				summaryLabelStyle.Render(i18n.T("TOTAL VALUE")),
 				summaryValueStyle.Render(visValue),
 			),
 		)
 
 		costCard := cardStyle.Render(
 			lipgloss.JoinVertical(lipgloss.Left,
-				summaryLabelStyle.Render("TOTAL COST"),
+				summaryLabelStyle.Render(i18n.T("TOTAL COST")),
 				summaryValueStyle.Render(visCost),
 			),
 		)
@@ -76,7 +78,7 @@ func (m Model) ViewPortfolio() string {
 
 		plCard := cardStyle.Render(
 			lipgloss.JoinVertical(lipgloss.Left,
-				summaryLabelStyle.Render("TOTAL P/L"),
+				summaryLabelStyle.Render(i18n.T("TOTAL P/L")),
 				plStyle.Render(plCardContent),
 			),
 		)
@@ -85,17 +87,26 @@ func (m Model) ViewPortfolio() string {
 		b.WriteString("\n\n")
 	}
 
+	// Portfolio rules currently met
+	for _, alert := range m.portfolioAlerts {
+		b.WriteString(redStyle.Render("⚠️ " + alert))
+		b.WriteString("\n")
+	}
+	if len(m.portfolioAlerts) > 0 {
+		b.WriteString("\n")
+	}
+
 	// Portfolio table
 	b.WriteString(m.portfolioTable.View())
 	b.WriteString("\n\n")
 
 	// Status bar
 	statusParts := []string{
-		fmt.Sprintf("Interval: %ds", m.cfg.Interval),
-		fmt.Sprintf("Holdings: %d", m.holdingsCount),
+		i18n.T("Interval: %ds", m.cfg.Interval),
+		i18n.T("Holdings: %d", m.holdingsCount),
 	}
 	if !m.lastRefresh.IsZero() {
-		statusParts = append(statusParts, fmt.Sprintf("Last: %s", m.lastRefresh.Format("15:04:05")))
+		statusParts = append(statusParts, i18n.T("Last: %s", m.lastRefresh.Format("15:04:05")))
 	}
 	if m.statusMessage != "" {
 		statusParts = append(statusParts, m.statusMessage)
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/congregalis/stock-ping/i18n"
+	"github.com/congregalis/stock-ping/indicator"
 	"github.com/guptarohit/asciigraph"
 )
 
@@ -13,27 +15,37 @@ func (m Model) ViewTrend() string {
 	var b strings.Builder
 
 	// Title / Header
-	title := titleStyle.Render(fmt.Sprintf("📈 Trend: %s", m.selectedSymbol))
+	title := titleStyle.Render(i18n.T("📈 Trend: %s", m.selectedSymbol))
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
 	// Content
 	if m.trendLoading {
-		b.WriteString("Loading trend data...\n")
+		b.WriteString(i18n.T("Loading trend data...\n"))
 	} else if m.trendError != nil {
-		b.WriteString(redStyle.Render(fmt.Sprintf("Error loading data: %v", m.trendError)))
+		b.WriteString(redStyle.Render(i18n.T("Error loading data: %v", m.trendError)))
 		b.WriteString("\n\n")
 	} else if m.trendData != nil {
 		// Display basic info
 		data, ok := m.stocks[m.selectedSymbol]
 		if ok {
-			info := fmt.Sprintf("Price: $%.2f • Change: %.2f%%", data.Price, data.Change)
+			info := i18n.T("Price: $%.2f • Change: %.2f%%", data.Price, data.Change)
 			if data.Change >= 0 {
 				b.WriteString(greenStyle.Render(info))
 			} else {
 				b.WriteString(redStyle.Render(info))
 			}
-			b.WriteString("\n\n")
+			b.WriteString("\n")
+		}
+		b.WriteString(dimStyle.Render(m.trendIndicators()))
+		b.WriteString("\n\n")
+
+		// Only the last trendDays bars are plotted, the rest warm up the indicators
+		closes := indicator.Closes(m.trendData)
+		sma := indicator.SMA(closes, 20)
+		start := len(closes) - trendDays
+		if start < 0 {
+			start = 0
 		}
 
 		// Render Chart using asciigraph
@@ -49,22 +61,24 @@ func (m Model) ViewTrend() string {
 		}
 
 		// Configure chart
-		graph := asciigraph.Plot(
-			m.trendData.C,
+		graph := asciigraph.PlotMany(
+			[][]float64{closes[start:], sma[start:]},
 			asciigraph.Height(chartHeight),
 			asciigraph.Width(width),
 			asciigraph.Precision(2),
 			asciigraph.SeriesColors(
 				asciigraph.Blue,
+				asciigraph.Yellow,
 			),
+			asciigraph.SeriesLegends(i18n.T("Close"), "SMA 20"),
 		)
 
 		b.WriteString(graph)
 		b.WriteString("\n")
 
 		// Add X-Axis Labels (manual)
-		if len(m.trendData.T) > 0 {
-			firstTime := time.Unix(m.trendData.T[0], 0)
+		if len(m.trendData.T) > start {
+			firstTime := time.Unix(m.trendData.T[start], 0)
 			lastTime := time.Unix(m.trendData.T[len(m.trendData.T)-1], 0)
 
 			startLabel := firstTime.Format("2006-01-02")
@@ -92,7 +106,36 @@ func (m Model) ViewTrend() string {
 	}
 
 	// Footer / Help
-	b.WriteString(mutedStyle.Render("Press [Esc] to return"))
+	b.WriteString(mutedStyle.Render(i18n.T("Press [Esc] to return")))
 
 	return b.String()
 }
+
+// trendIndicators summarizes the latest indicator values for the trend data
+func (m Model) trendIndicators() string {
+	closes := indicator.Closes(m.trendData)
+
+	var parts []string
+	if v, ok := indicator.Last(indicator.RSI(closes, 14)); ok {
+		parts = append(parts, fmt.Sprintf("RSI(14) %.2f", v))
+	}
+	macd := indicator.MACD(closes, 12, 26, 9)
+	if line, ok := indicator.Last(macd.MACD); ok {
+		if sig, ok := indicator.Last(macd.Signal); ok {
+			parts = append(parts, fmt.Sprintf("MACD %.2f / %.2f", line, sig))
+		}
+	}
+	bb := indicator.Bollinger(closes, 20, 2)
+	if upper, ok := indicator.Last(bb.Upper); ok {
+		lower, _ := indicator.Last(bb.Lower)
+		parts = append(parts, fmt.Sprintf("BB(20) $%.2f ~ $%.2f", lower, upper))
+	}
+	if v, ok := indicator.Last(indicator.ATR(m.trendData, 14)); ok {
+		parts = append(parts, fmt.Sprintf("ATR(14) $%.2f", v))
+	}
+
+	if len(parts) == 0 {
+		return i18n.T("Not enough data for indicators")
+	}
+	return strings.Join(parts, " • ")
+}
@@ -2,6 +2,7 @@ package watcher
 
 import (
 	"log"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -26,13 +27,14 @@ func NewConfigWatcher(path string, callback func(*config.Config)) (*ConfigWatche
 	}
 
 	cw := &ConfigWatcher{
-		path:     path,
+		path:     filepath.Clean(path),
 		watcher:  watcher,
 		callback: callback,
 		done:     make(chan struct{}),
 	}
 
-	if err := watcher.Add(path); err != nil {
+	// Watch the directory, since saving by rename replaces the file itself
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
 		watcher.Close()
 		return nil, err
 	}
@@ -63,8 +65,8 @@ func (cw *ConfigWatcher) watchLoop() {
 				return
 			}
 
-			// Only react to write/create events
-			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+			// Only react to write/create events of the config file
+			if filepath.Clean(event.Name) == cw.path && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
 				cw.mu.Lock()
 				if debounceTimer != nil {
 					debounceTimer.Stop()